@@ -0,0 +1,371 @@
+package goat
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Maps the linear progress of an animation, from 0 to 1, to the value it is shown at.
+// Curves start at 0 and end at 1, but may overshoot in between.
+type Curve func(t float64) float64
+
+var (
+	CurveLinear Curve = func(t float64) float64 { return t }
+
+	// Same as the ease-in, ease-out and ease-in-out timing functions of CSS
+	CurveEaseIn    = CurveCubicBezier(0.42, 0, 1, 1)
+	CurveEaseOut   = CurveCubicBezier(0, 0, 0.58, 1)
+	CurveEaseInOut = CurveCubicBezier(0.42, 0, 0.58, 1)
+
+	CurveEaseInCubic    Curve = func(t float64) float64 { return t * t * t }
+	CurveEaseOutCubic   Curve = func(t float64) float64 { return 1 - math.Pow(1-t, 3) }
+	CurveEaseInOutCubic Curve = func(t float64) float64 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		return 1 - math.Pow(-2*t+2, 3)/2
+	}
+
+	// Overshoots the end slightly before settling on it
+	CurveEaseOutBack Curve = func(t float64) float64 {
+		const c1 = 1.70158
+		const c3 = c1 + 1
+		return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+	}
+
+	// Bounces against the end, like a dropped ball
+	CurveBounceOut Curve = func(t float64) float64 {
+		const n1 = 7.5625
+		const d1 = 2.75
+		switch {
+		case t < 1/d1:
+			return n1 * t * t
+		case t < 2/d1:
+			t -= 1.5 / d1
+			return n1*t*t + 0.75
+		case t < 2.5/d1:
+			t -= 2.25 / d1
+			return n1*t*t + 0.9375
+		default:
+			t -= 2.625 / d1
+			return n1*t*t + 0.984375
+		}
+	}
+)
+
+// Returns a curve following the cubic Bézier from (0, 0) to (1, 1) with the control points (x1, y1) and (x2, y2), like cubic-bezier() in CSS.
+// The x coordinates are clamped between 0 and 1, so that there is only one value for each point in time.
+func CurveCubicBezier(x1, y1, x2, y2 float64) Curve {
+	x1 = min(max(x1, 0), 1)
+	x2 = min(max(x2, 0), 1)
+
+	bezier := func(s, p1, p2 float64) float64 {
+		return 3*(1-s)*(1-s)*s*p1 + 3*(1-s)*s*s*p2 + s*s*s
+	}
+	bezierSlope := func(s, p1, p2 float64) float64 {
+		return 3*(1-s)*(1-s)*p1 + 6*(1-s)*s*(p2-p1) + 3*s*s*(1-p2)
+	}
+
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+
+		// Find the point on the curve at x = t with Newton's method, falling back to bisection where the slope is too flat
+		s := t
+		for i := 0; i < 8; i++ {
+			diff := bezier(s, x1, x2) - t
+			if math.Abs(diff) < 1e-7 {
+				return bezier(s, y1, y2)
+			}
+			slope := bezierSlope(s, x1, x2)
+			if math.Abs(slope) < 1e-6 {
+				break
+			}
+			s -= diff / slope
+		}
+
+		low, high := 0.0, 1.0
+		s = t
+		for i := 0; i < 32; i++ {
+			x := bezier(s, x1, x2)
+			if math.Abs(x-t) < 1e-7 {
+				break
+			}
+			if x < t {
+				low = s
+			} else {
+				high = s
+			}
+			s = (low + high) / 2
+		}
+
+		return bezier(s, y1, y2)
+	}
+}
+
+// Types that a Tween can interpolate between
+type Tweenable interface {
+	int | float64 | Color
+}
+
+// Interpolates between a beginning and ending value, such as to turn the value of an animation into a color
+type Tween[T Tweenable] struct {
+	Begin T
+	End   T
+}
+
+// Returns the value at t, where 0 is Begin and 1 is End. Ints are rounded to the nearest value, and colors have each of their channels interpolated.
+func (tween Tween[T]) Lerp(t float64) T {
+	switch begin := any(tween.Begin).(type) {
+	case int:
+		end := any(tween.End).(int)
+		return any(int(math.Round(float64(begin) + float64(end-begin)*t))).(T)
+	case float64:
+		end := any(tween.End).(float64)
+		return any(begin + (end-begin)*t).(T)
+	case Color:
+		end := any(tween.End).(Color)
+		lerpChannel := func(begin, end uint8) uint8 {
+			return uint8(min(max(math.Round(float64(begin)+(float64(end)-float64(begin))*t), 0), 0xff))
+		}
+		return any(Color{
+			R: lerpChannel(begin.R, end.R),
+			G: lerpChannel(begin.G, end.G),
+			B: lerpChannel(begin.B, end.B),
+			A: lerpChannel(begin.A, end.A),
+		}).(T)
+	}
+
+	panic("unreachable")
+}
+
+// Returns the value at the current value of the animation
+func (tween Tween[T]) Evaluate(animation *AnimationController) T {
+	return tween.Lerp(animation.Value())
+}
+
+type AnimationStatus int
+
+const (
+	// Stopped at the beginning
+	AnimationDismissed AnimationStatus = iota
+	// Running, or stopped part way through, towards the end
+	AnimationForward
+	// Running, or stopped part way through, towards the beginning
+	AnimationReverse
+	// Stopped at the end
+	AnimationCompleted
+)
+
+// How often widgets with a running animation rerender
+const animationFrameInterval = 16 * time.Millisecond
+
+// Controls an animation created by UseAnimation. It is safe to use from event listeners and other goroutines.
+type AnimationController struct {
+	lock          sync.Mutex
+	duration      time.Duration
+	curve         Curve
+	triggerRender func()
+
+	running       bool
+	reverse       bool
+	repeat        bool
+	repeatReverse bool
+	// Progress when the animation was last started, and the time it was started at.
+	// When repeating in reverse, the progress is a phase from 0 to 2, where the second half goes back from the end.
+	startProgress float64
+	startTime     time.Time
+	// Linear progress from 0 to 1, as of the last build or change to the animation
+	progress float64
+}
+
+// A hook that creates an animation, which rerenders the widget every frame while it runs.
+// The animation starts stopped at the beginning, and is stopped when the widget is unmounted.
+// The curve defaults to CurveLinear when nil, and the duration is how long the animation takes to go from the beginning to the end.
+func UseAnimation(duration time.Duration, curve Curve) *AnimationController {
+	triggerRender := UseTriggerRender()
+	animation := UseRefFunc(func() *AnimationController {
+		return &AnimationController{
+			duration:      duration,
+			triggerRender: triggerRender,
+		}
+	})
+
+	animation.lock.Lock()
+	now := time.Now()
+	animation.advance(now)
+	if duration != animation.duration {
+		// Keep going from the current progress, at the new speed
+		animation.restart(now)
+		animation.duration = duration
+	}
+	animation.curve = curve
+	running := animation.running
+	animation.lock.Unlock()
+
+	frameInterval := time.Duration(0)
+	if running {
+		frameInterval = animationFrameInterval
+	}
+	UseFrameClock(frameInterval)
+
+	return animation
+}
+
+// Updates the progress of a running animation to the given time, stopping it if it has reached its end
+func (a *AnimationController) advance(now time.Time) {
+	if !a.running {
+		return
+	}
+
+	// Without a duration there is nothing to show in between, so the animation finishes straight away, even when repeating
+	if a.duration <= 0 {
+		a.progress = 1
+		if a.reverse && !a.repeat {
+			a.progress = 0
+		}
+		a.running = false
+		return
+	}
+
+	delta := float64(now.Sub(a.startTime)) / float64(a.duration)
+
+	switch {
+	case a.repeat && a.repeatReverse:
+		phase := math.Mod(a.startProgress+delta, 2)
+		a.reverse = phase > 1
+		a.progress = phase
+		if a.reverse {
+			a.progress = 2 - phase
+		}
+	case a.repeat:
+		a.progress = math.Mod(a.startProgress+delta, 1)
+	case a.reverse:
+		a.progress = max(a.startProgress-delta, 0)
+		a.running = a.progress > 0
+	default:
+		a.progress = min(a.startProgress+delta, 1)
+		a.running = a.progress < 1
+	}
+}
+
+// Restarts the animation from its current progress at the given time
+func (a *AnimationController) restart(now time.Time) {
+	a.startProgress = a.progress
+	if a.repeat && a.repeatReverse && a.reverse {
+		a.startProgress = 2 - a.progress
+	}
+	a.startTime = now
+}
+
+func (a *AnimationController) start(reverse, repeat, repeatReverse bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := time.Now()
+	a.advance(now)
+	a.running = true
+	a.reverse = reverse
+	a.repeat = repeat
+	a.repeatReverse = repeatReverse
+	a.restart(now)
+
+	a.triggerRender()
+}
+
+// Runs the animation from its current progress to the end
+func (a *AnimationController) Forward() {
+	a.start(false, false, false)
+}
+
+// Runs the animation from its current progress back to the beginning
+func (a *AnimationController) Reverse() {
+	a.start(true, false, false)
+}
+
+// Runs the animation until it is stopped, jumping back to the beginning each time it reaches the end.
+// If reverse is true, then it instead goes back and forth between the beginning and the end.
+// An animation with a zero duration cannot repeat, so it stops at the end instead.
+func (a *AnimationController) Repeat(reverse bool) {
+	a.lock.Lock()
+	direction := reverse && a.reverse
+	a.lock.Unlock()
+
+	a.start(direction, true, reverse)
+}
+
+// Stops the animation at its current progress
+func (a *AnimationController) Stop() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.advance(time.Now())
+	a.running = false
+	a.triggerRender()
+}
+
+// Stops the animation and returns it to the beginning
+func (a *AnimationController) Reset() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.running = false
+	a.reverse = false
+	a.progress = 0
+	a.triggerRender()
+}
+
+// Jumps to the given progress, from 0 to 1. A running animation carries on from there.
+func (a *AnimationController) SetProgress(progress float64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.progress = min(max(progress, 0), 1)
+	a.restart(time.Now())
+	a.triggerRender()
+}
+
+// Returns the linear progress of the animation, from 0 at the beginning to 1 at the end
+func (a *AnimationController) Progress() float64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.progress
+}
+
+// Returns the progress of the animation after it is passed through the curve
+func (a *AnimationController) Value() float64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.curve == nil {
+		return a.progress
+	}
+	return a.curve(a.progress)
+}
+
+func (a *AnimationController) Status() AnimationStatus {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	switch {
+	case !a.running && a.progress == 0:
+		return AnimationDismissed
+	case !a.running && a.progress == 1:
+		return AnimationCompleted
+	case a.reverse:
+		return AnimationReverse
+	default:
+		return AnimationForward
+	}
+}
+
+// Reports whether the animation is running
+func (a *AnimationController) IsAnimating() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.running
+}
@@ -0,0 +1,129 @@
+package goat
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnimationControllerAdvance(t *testing.T) {
+	tests := []struct {
+		name       string
+		controller *AnimationController
+		elapsed    time.Duration
+
+		wantProgress float64
+		wantRunning  bool
+		wantReverse  bool
+	}{
+		{
+			name:         "forward part way",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true},
+			elapsed:      50 * time.Millisecond,
+			wantProgress: 0.5,
+			wantRunning:  true,
+		},
+		{
+			name:         "forward from where it was started",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, startProgress: 0.25},
+			elapsed:      50 * time.Millisecond,
+			wantProgress: 0.75,
+			wantRunning:  true,
+		},
+		{
+			name:         "forward stops at the end",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true},
+			elapsed:      150 * time.Millisecond,
+			wantProgress: 1,
+			wantRunning:  false,
+		},
+		{
+			name:         "reverse part way",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, reverse: true, startProgress: 1},
+			elapsed:      25 * time.Millisecond,
+			wantProgress: 0.75,
+			wantRunning:  true,
+			wantReverse:  true,
+		},
+		{
+			name:         "reverse stops at the beginning",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, reverse: true, startProgress: 0.5},
+			elapsed:      200 * time.Millisecond,
+			wantProgress: 0,
+			wantRunning:  false,
+			wantReverse:  true,
+		},
+		{
+			name:         "repeat jumps back to the beginning",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, repeat: true},
+			elapsed:      130 * time.Millisecond,
+			wantProgress: 0.3,
+			wantRunning:  true,
+		},
+		{
+			name:         "repeat in reverse goes back from the end",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, repeat: true, repeatReverse: true},
+			elapsed:      130 * time.Millisecond,
+			wantProgress: 0.7,
+			wantRunning:  true,
+			wantReverse:  true,
+		},
+		{
+			name:         "repeat in reverse goes forward again",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, running: true, repeat: true, repeatReverse: true},
+			elapsed:      230 * time.Millisecond,
+			wantProgress: 0.3,
+			wantRunning:  true,
+		},
+		{
+			name:         "stopped animation is left alone",
+			controller:   &AnimationController{duration: 100 * time.Millisecond, progress: 0.4},
+			elapsed:      50 * time.Millisecond,
+			wantProgress: 0.4,
+			wantRunning:  false,
+		},
+		{
+			name:         "zero duration finishes forward straight away",
+			controller:   &AnimationController{running: true},
+			wantProgress: 1,
+			wantRunning:  false,
+		},
+		{
+			name:         "zero duration finishes reverse straight away",
+			controller:   &AnimationController{running: true, reverse: true, startProgress: 1, progress: 1},
+			wantProgress: 0,
+			wantRunning:  false,
+			wantReverse:  true,
+		},
+		{
+			name:         "zero duration stops repeating at the end",
+			controller:   &AnimationController{running: true, repeat: true, repeatReverse: true, reverse: true},
+			elapsed:      time.Second,
+			wantProgress: 1,
+			wantRunning:  false,
+			wantReverse:  true,
+		},
+		{
+			name:         "negative duration finishes straight away",
+			controller:   &AnimationController{duration: -time.Second, running: true},
+			elapsed:      time.Millisecond,
+			wantProgress: 1,
+			wantRunning:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			a := test.controller
+			a.startTime = start
+
+			a.advance(start.Add(test.elapsed))
+
+			if math.Abs(a.progress-test.wantProgress) > 1e-9 || a.running != test.wantRunning || a.reverse != test.wantReverse {
+				t.Errorf("got progress %v, running %v and reverse %v, want %v, %v and %v",
+					a.progress, a.running, a.reverse, test.wantProgress, test.wantRunning, test.wantReverse)
+			}
+		})
+	}
+}
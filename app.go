@@ -6,11 +6,41 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 )
 
-func RunApp(w Widget) error {
+// Changes how RunApp runs the app
+type AppOption func(options *appOptions)
+
+type appOptions struct {
+	colorDepth ColorDepth
+	dither     bool
+}
+
+// Draws colors with the given depth, instead of the one detected from the terminal
+func WithColorDepth(depth ColorDepth) AppOption {
+	return func(options *appOptions) {
+		options.colorDepth = depth
+	}
+}
+
+// Dithers background colors when the terminal cannot display every color, so that gradients look smooth instead of banded
+func WithDithering() AppOption {
+	return func(options *appOptions) {
+		options.dither = true
+	}
+}
+
+// Runs the app in the terminal until Ctrl+C is pressed or the process is interrupted.
+// Since Ctrl+C quits, it cannot be used as a shortcut by widgets, such as for copying text.
+func RunApp(w Widget, options ...AppOption) error {
+	appliedOptions := appOptions{}
+	for _, option := range options {
+		option(&appliedOptions)
+	}
+
 	screen, err := tcell.NewScreen()
 	if err != nil {
 		return err
@@ -21,12 +51,25 @@ func RunApp(w Widget) error {
 		return err
 	}
 
+	colorDepth := appliedOptions.colorDepth
+	if colorDepth == ColorDepthAuto {
+		colorDepth = detectColorDepth(screen)
+	}
+	colors := newColorRenderer(colorDepth, appliedOptions.dither)
+
 	screen.EnableMouse()
 	screen.EnablePaste()
 	screen.Clear()
 
+	// Held while writing to the terminal, so that drawing and the clipboard's escape sequences are not interleaved
+	terminalLock := &sync.Mutex{}
+	if tty, ok := screen.Tty(); ok {
+		Clipboard.setTerminal(tty, terminalLock)
+	}
+
 	quit := func() {
 		maybePanic := recover()
+		Clipboard.setTerminal(nil, nil)
 		screen.Fini()
 		if maybePanic != nil {
 			panic(maybePanic)
@@ -54,26 +97,45 @@ func RunApp(w Widget) error {
 	// due to widget Build() methods altering event listeners
 	treeLock := sync.Mutex{}
 
+	rootElement := &Element{}
+
+	// Each element's listeners are called in the order events happened, while different elements are called concurrently so a slow listener only delays its own element
+	listeners := &listenerQueue{
+		pending: make(map[*Element][]func()),
+		onPanic: quit,
+	}
+
 	handleEvent := func(event tcell.Event) {
 		switch event := event.(type) {
 		case *tcell.EventKey:
-			if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC {
+			// Ctrl+C always quits, so it is never delivered to widgets, which copy with Ctrl+Insert instead
+			if event.Key() == tcell.KeyCtrlC {
 				quitRenderChan <- struct{}{}
 				return
 			}
+
+			if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+				treeLock.Lock()
+				moved := moveFocus(eventRoots(rootElement, nil), event.Key() == tcell.KeyBacktab)
+				treeLock.Unlock()
+
+				if moved {
+					return
+				}
+			}
 		}
 
 		treeLock.Lock()
-		defer treeLock.Unlock()
-
-		for elem, listeners := range globalHookEventListeners {
-			for _, listener := range listeners {
-				go listener(EventContext{
-					Event:      event,
-					RenderPos:  elem.renderAbsPos,
-					RenderSize: elem.size,
-				})
-			}
+
+		var calls []listenerCall
+		for _, root := range eventRoots(rootElement, event) {
+			collectListenerCalls(root, event, &calls)
+		}
+
+		treeLock.Unlock()
+
+		for _, call := range calls {
+			listeners.push(call.element, call.call)
 		}
 	}
 
@@ -85,8 +147,6 @@ func RunApp(w Widget) error {
 		}
 	}()
 
-	rootElement := &Element{}
-
 	// debugFile, _ := os.Create("debug-tree.txt")
 	// debugFileSize := 0
 
@@ -102,6 +162,7 @@ func RunApp(w Widget) error {
 		select {
 		case <-quitRenderChan:
 			destroyTree(rootElement)
+			destroyOverlays()
 			return nil
 		default:
 		}
@@ -111,40 +172,127 @@ func RunApp(w Widget) error {
 
 		treeLock.Lock()
 
+		advanceFrameClock(time.Now(), rootConstraints.Max)
+
 		err = rebuildTree(w, rootElement, rootConstraints)
 		if err != nil {
 			return fmt.Errorf("build error: %w", err)
 		}
 
+		err = rebuildOverlays(rootConstraints.Max)
+		if err != nil {
+			return fmt.Errorf("build error: %w", err)
+		}
+
 		canvas, err := renderTree(rootElement)
 		if err != nil {
 			return fmt.Errorf("render error: %w", err)
 		}
 
+		err = renderOverlays(&canvas)
+		if err != nil {
+			return fmt.Errorf("render error: %w", err)
+		}
+
 		treeLock.Unlock()
 
-		drawCanvasToScreen(canvas, screen)
+		terminalLock.Lock()
+		drawCanvasToScreen(canvas, screen, colors)
+		terminalLock.Unlock()
 
 		// debugTree()
 	}
 }
 
-func drawCanvasToScreen(canvas Canvas, screen tcell.Screen) {
-	width := canvas.size.Width.Int()
+// A listener of an element, bound to the event it is called with
+type listenerCall struct {
+	element *Element
+	call    func()
+}
 
-	for i, cell := range canvas.cells {
-		style := tcell.StyleDefault.
-			Foreground(tcell.NewRGBColor(int32(cell.Foreground.R), int32(cell.Foreground.G), int32(cell.Foreground.B))).
-			Background(tcell.NewRGBColor(int32(cell.Background.R), int32(cell.Background.G), int32(cell.Background.B)))
+// Adds a call to every event listener in the tree to calls, with parents being called before their children
+func collectListenerCalls(thisElement *Element, event tcell.Event, calls *[]listenerCall) {
+	context := EventContext{
+		Event:      event,
+		RenderPos:  thisElement.renderAbsPos,
+		RenderSize: thisElement.size,
+		Focused:    isFocused(thisElement),
+	}
+	for _, listener := range globalHookEventListeners[thisElement] {
+		*calls = append(*calls, listenerCall{
+			element: thisElement,
+			call: func() {
+				listener(context)
+			},
+		})
+	}
+
+	for _, key := range sortedChildKeys(thisElement) {
+		if !thisElement.children[key].offstage {
+			collectListenerCalls(thisElement.children[key], event, calls)
+		}
+	}
+}
+
+// Calls listeners on a goroutine per element, which only runs while the element has calls waiting
+type listenerQueue struct {
+	lock sync.Mutex
+	// Calls waiting for each element, which is only present while its goroutine is running
+	pending map[*Element][]func()
+	// Restores the terminal before a panicking listener crashes the app
+	onPanic func()
+}
+
+func (q *listenerQueue) push(element *Element, call func()) {
+	q.lock.Lock()
+	calls, running := q.pending[element]
+	q.pending[element] = append(calls, call)
+	q.lock.Unlock()
+
+	if !running {
+		go q.drain(element)
+	}
+}
+
+func (q *listenerQueue) drain(element *Element) {
+	defer func() {
+		if maybePanic := recover(); maybePanic != nil {
+			q.onPanic()
+			panic(maybePanic)
+		}
+	}()
 
-		const uint8Midpoint = 0xFF / 2
+	for {
+		q.lock.Lock()
+		calls := q.pending[element]
+		if len(calls) == 0 {
+			delete(q.pending, element)
+			q.lock.Unlock()
+			return
+		}
+		q.pending[element] = []func(){}
+		q.lock.Unlock()
 
-		// If opacity is less than half, then use terminal default color
-		if cell.Foreground.A < uint8Midpoint {
-			style = style.Foreground(tcell.ColorDefault)
+		for _, call := range calls {
+			call()
 		}
-		if cell.Background.A < uint8Midpoint {
-			style = style.Background(tcell.ColorDefault)
+	}
+}
+
+func drawCanvasToScreen(canvas Canvas, screen tcell.Screen, colors *colorRenderer) {
+	width := canvas.size.Width.Int()
+
+	for i, cell := range canvas.cells {
+		// Translucent colors are blended with the terminal's default colors, and fully transparent colors are left as the defaults
+		style := tcell.StyleDefault
+		x, y := i%width, i/width
+		background := DefaultBackground
+		if cell.Background.A != 0 {
+			background = DefaultBackground.Blend(cell.Background)
+			style = style.Background(colors.backgroundColor(background, x, y))
+		}
+		if cell.Foreground.A != 0 {
+			style = style.Foreground(colors.terminalColor(background.Blend(cell.Foreground)))
 		}
 
 		if cell.TextStyle != nil {
@@ -168,6 +316,9 @@ func drawCanvasToScreen(canvas Canvas, screen tcell.Screen) {
 				if cell.TextStyle.StrikeThrough {
 					attr |= tcell.AttrStrikeThrough
 				}
+				if cell.TextStyle.Reverse {
+					attr |= tcell.AttrReverse
+				}
 			}
 
 			style = style.
@@ -176,7 +327,22 @@ func drawCanvasToScreen(canvas Canvas, screen tcell.Screen) {
 				UrlId(cell.TextStyle.UrlId)
 		}
 
-		screen.SetContent(i%width, i/width, cell.Rune, nil, style)
+		if cell.Background.A != 0 && colors.reverseBackground(background) {
+			style = style.Reverse(true)
+		}
+
+		// The terminal draws wide graphemes over their continuation cells
+		if cell.Continuation {
+			continue
+		}
+
+		mainc, combc := ' ', []rune(nil)
+		if cell.Grapheme != "" {
+			runes := []rune(cell.Grapheme)
+			mainc, combc = runes[0], runes[1:]
+		}
+
+		screen.SetContent(x, y, mainc, combc, style)
 	}
 
 	screen.Show()
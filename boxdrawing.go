@@ -0,0 +1,91 @@
+package goat
+
+// Weight of a line drawn by a box drawing character
+type boxLine uint8
+
+const (
+	boxLineNone boxLine = iota
+	boxLineLight
+	boxLineHeavy
+	boxLineDouble
+)
+
+// Lines leaving the center of a box drawing character, in the order up, right, down, left
+type boxArms [4]boxLine
+
+// Box drawing characters and the lines they are made of, in code point order
+var boxDrawingArms = map[string]boxArms{
+	"─": {0, 1, 0, 1}, "━": {0, 2, 0, 2}, "│": {1, 0, 1, 0}, "┃": {2, 0, 2, 0},
+	"┄": {0, 1, 0, 1}, "┅": {0, 2, 0, 2}, "┆": {1, 0, 1, 0}, "┇": {2, 0, 2, 0},
+	"┈": {0, 1, 0, 1}, "┉": {0, 2, 0, 2}, "┊": {1, 0, 1, 0}, "┋": {2, 0, 2, 0},
+	"┌": {0, 1, 1, 0}, "┍": {0, 2, 1, 0}, "┎": {0, 1, 2, 0}, "┏": {0, 2, 2, 0},
+	"┐": {0, 0, 1, 1}, "┑": {0, 0, 1, 2}, "┒": {0, 0, 2, 1}, "┓": {0, 0, 2, 2},
+	"└": {1, 1, 0, 0}, "┕": {1, 2, 0, 0}, "┖": {2, 1, 0, 0}, "┗": {2, 2, 0, 0},
+	"┘": {1, 0, 0, 1}, "┙": {1, 0, 0, 2}, "┚": {2, 0, 0, 1}, "┛": {2, 0, 0, 2},
+	"├": {1, 1, 1, 0}, "┝": {1, 2, 1, 0}, "┞": {2, 1, 1, 0}, "┟": {1, 1, 2, 0},
+	"┠": {2, 1, 2, 0}, "┡": {2, 2, 1, 0}, "┢": {1, 2, 2, 0}, "┣": {2, 2, 2, 0},
+	"┤": {1, 0, 1, 1}, "┥": {1, 0, 1, 2}, "┦": {2, 0, 1, 1}, "┧": {1, 0, 2, 1},
+	"┨": {2, 0, 2, 1}, "┩": {2, 0, 1, 2}, "┪": {1, 0, 2, 2}, "┫": {2, 0, 2, 2},
+	"┬": {0, 1, 1, 1}, "┭": {0, 1, 1, 2}, "┮": {0, 2, 1, 1}, "┯": {0, 2, 1, 2},
+	"┰": {0, 1, 2, 1}, "┱": {0, 1, 2, 2}, "┲": {0, 2, 2, 1}, "┳": {0, 2, 2, 2},
+	"┴": {1, 1, 0, 1}, "┵": {1, 1, 0, 2}, "┶": {1, 2, 0, 1}, "┷": {1, 2, 0, 2},
+	"┸": {2, 1, 0, 1}, "┹": {2, 1, 0, 2}, "┺": {2, 2, 0, 1}, "┻": {2, 2, 0, 2},
+	"┼": {1, 1, 1, 1}, "┽": {1, 1, 1, 2}, "┾": {1, 2, 1, 1}, "┿": {1, 2, 1, 2},
+	"╀": {2, 1, 1, 1}, "╁": {1, 1, 2, 1}, "╂": {2, 1, 2, 1}, "╃": {2, 1, 1, 2},
+	"╄": {2, 2, 1, 1}, "╅": {1, 1, 2, 2}, "╆": {1, 2, 2, 1}, "╇": {2, 2, 1, 2},
+	"╈": {1, 2, 2, 2}, "╉": {2, 1, 2, 2}, "╊": {2, 2, 2, 1}, "╋": {2, 2, 2, 2},
+	"╌": {0, 1, 0, 1}, "╍": {0, 2, 0, 2}, "╎": {1, 0, 1, 0}, "╏": {2, 0, 2, 0},
+	"═": {0, 3, 0, 3}, "║": {3, 0, 3, 0}, "╒": {0, 3, 1, 0}, "╓": {0, 1, 3, 0},
+	"╔": {0, 3, 3, 0}, "╕": {0, 0, 1, 3}, "╖": {0, 0, 3, 1}, "╗": {0, 0, 3, 3},
+	"╘": {1, 3, 0, 0}, "╙": {3, 1, 0, 0}, "╚": {3, 3, 0, 0}, "╛": {1, 0, 0, 3},
+	"╜": {3, 0, 0, 1}, "╝": {3, 0, 0, 3}, "╞": {1, 3, 1, 0}, "╟": {3, 1, 3, 0},
+	"╠": {3, 3, 3, 0}, "╡": {1, 0, 1, 3}, "╢": {3, 0, 3, 1}, "╣": {3, 0, 3, 3},
+	"╤": {0, 3, 1, 3}, "╥": {0, 1, 3, 1}, "╦": {0, 3, 3, 3}, "╧": {1, 3, 0, 3},
+	"╨": {3, 1, 0, 1}, "╩": {3, 3, 0, 3}, "╪": {1, 3, 1, 3}, "╫": {3, 1, 3, 1},
+	"╬": {3, 3, 3, 3}, "╭": {0, 1, 1, 0}, "╮": {0, 0, 1, 1}, "╯": {1, 0, 0, 1},
+	"╰": {1, 1, 0, 0}, "╴": {0, 0, 0, 1}, "╵": {1, 0, 0, 0}, "╶": {0, 1, 0, 0},
+	"╷": {0, 0, 1, 0}, "╸": {0, 0, 0, 2}, "╹": {2, 0, 0, 0}, "╺": {0, 2, 0, 0},
+	"╻": {0, 0, 2, 0}, "╼": {0, 2, 0, 1}, "╽": {1, 0, 2, 0}, "╾": {0, 1, 0, 2},
+	"╿": {2, 0, 1, 0},
+}
+
+// The character for each combination of lines.
+// Dashed and rounded characters come after the solid ones with the same lines, so they are never chosen when joining.
+var boxDrawingGraphemes = func() map[boxArms]string {
+	result := make(map[boxArms]string)
+	for grapheme, arms := range boxDrawingArms {
+		existing, ok := result[arms]
+		if !ok || grapheme < existing {
+			result[arms] = grapheme
+		}
+	}
+	return result
+}()
+
+// Returns the box drawing character with the lines of both characters, such as "┼" for "─" and "│".
+// Lines of the top character take precedence, and false is returned if either is not a box drawing character, or no character joins them.
+func joinBoxDrawing(bottom, top string) (string, bool) {
+	bottomArms, ok := boxDrawingArms[bottom]
+	if !ok {
+		return "", false
+	}
+	topArms, ok := boxDrawingArms[top]
+	if !ok {
+		return "", false
+	}
+
+	arms := topArms
+	for i, line := range arms {
+		if line == boxLineNone {
+			arms[i] = bottomArms[i]
+		}
+	}
+
+	// Keep the top character when it already covers the bottom one, so dashed and rounded lines are not replaced
+	if arms == topArms {
+		return top, true
+	}
+
+	grapheme, ok := boxDrawingGraphemes[arms]
+	return grapheme, ok
+}
@@ -0,0 +1,35 @@
+package goat
+
+import "testing"
+
+func TestJoinBoxDrawing(t *testing.T) {
+	tests := []struct {
+		name        string
+		bottom, top string
+		want        string
+		wantOk      bool
+	}{
+		{name: "crossing lines", bottom: "─", top: "│", want: "┼", wantOk: true},
+		{name: "crossing lines the other way", bottom: "│", top: "─", want: "┼", wantOk: true},
+		{name: "corners meeting", bottom: "┌", top: "┐", want: "┬", wantOk: true},
+		{name: "heavy top line", bottom: "─", top: "┃", want: "╂", wantOk: true},
+		{name: "heavy bottom line", bottom: "┃", top: "─", want: "╂", wantOk: true},
+		{name: "double and light lines", bottom: "═", top: "│", want: "╪", wantOk: true},
+		{name: "rounded corner joined as solid", bottom: "─", top: "╭", want: "┬", wantOk: true},
+		{name: "same line", bottom: "│", top: "│", want: "│", wantOk: true},
+		{name: "dashed top line covering the bottom one is kept", bottom: "─", top: "┄", want: "┄", wantOk: true},
+		{name: "no character joins heavy and double lines", bottom: "═", top: "┃", wantOk: false},
+		{name: "bottom is not a box drawing character", bottom: "a", top: "─", wantOk: false},
+		{name: "top is not a box drawing character", bottom: "─", top: " ", wantOk: false},
+		{name: "empty cells", bottom: "", top: "", wantOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := joinBoxDrawing(test.bottom, test.top)
+			if ok != test.wantOk || got != test.want {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, test.want, test.wantOk)
+			}
+		})
+	}
+}
@@ -3,11 +3,18 @@ package goat
 import (
 	"fmt"
 	"image"
+
+	"github.com/rivo/uniseg"
 )
 
 type Cell struct {
-	// Default rune represents no change in text
-	Rune rune
+	// Grapheme cluster displayed in the cell. An empty string represents no change in text.
+	Grapheme string
+	// Number of columns taken up by the grapheme, which is 2 for wide graphemes such as CJK characters and most emoji.
+	// If left as zero when passed to Canvas.SetCell, then it is measured from the grapheme.
+	Width int
+	// Reports whether the cell is covered by the wide grapheme in the cell to its left
+	Continuation bool
 
 	Background, Foreground Color
 
@@ -15,26 +22,69 @@ type Cell struct {
 }
 
 type CellTextStyle struct {
-	Bold, Blink, Dim, Italic, Underline, StrikeThrough bool
+	Bold, Blink, Dim, Italic, Underline, StrikeThrough, Reverse bool
 
 	Url   string
 	UrlId string
 }
 
+// Returns the number of columns a grapheme cluster takes up in a terminal, which is either 1 or 2
+func GraphemeWidth(grapheme string) int {
+	return min(max(uniseg.StringWidth(grapheme), 1), 2)
+}
+
+// Reports whether the cell draws anything other than its background
+func (c Cell) hasInk() bool {
+	if c.Continuation || (c.Grapheme != "" && c.Grapheme != " ") {
+		return true
+	}
+
+	// Lines and reversed colors are drawn even over spaces
+	return c.TextStyle != nil && (c.TextStyle.Underline || c.TextStyle.StrikeThrough || c.TextStyle.Reverse)
+}
+
+// Returns the cell seen when the top cell is drawn over this one.
+//
+// The backgrounds are blended together, and a translucent foreground is blended with the resulting background once it is opaque, or otherwise when drawn to the screen.
+// Where the top cell draws nothing but its background, the grapheme of the bottom cell shows through it, unless the background is opaque.
 func (bottom Cell) Blend(top Cell) Cell {
 	result := Cell{
-		Rune:       top.Rune,
 		Background: bottom.Background.Blend(top.Background),
-		// TODO: take background into account with transparent foreground
-		Foreground: bottom.Foreground.Blend(top.Foreground),
 		TextStyle:  top.TextStyle,
 	}
 
-	// If top cell does not override the rune or text style, then fallback to the bottom cell's
-	if top.Rune == rune(0) {
-		result.Rune = bottom.Rune
+	switch {
+	case top.hasInk():
+		result.Grapheme = top.Grapheme
+		result.Width = top.Width
+		result.Continuation = top.Continuation
+		// A translucent foreground keeps its alpha until the background behind it is opaque, since layers below may still change what it is blended with
+		result.Foreground = top.Foreground
+		if top.Foreground.A != 0 && result.Background.A == 0xFF {
+			result.Foreground = result.Background.Blend(top.Foreground)
+		}
+	case top.Background.A == 0xFF:
+		result.Grapheme = " "
+		result.Width = 1
+		result.Foreground = top.Foreground
+	default:
+		result.Grapheme = bottom.Grapheme
+		result.Width = bottom.Width
+		result.Continuation = bottom.Continuation
+
+		// The bottom grapheme is seen through the top background, which tints it
+		result.Foreground = bottom.Foreground
+		if top.Background.A != 0 && bottom.hasInk() {
+			foreground := bottom.Foreground
+			if foreground.A == 0 {
+				foreground = DefaultForeground
+			}
+			result.Foreground = foreground.Blend(top.Background)
+		}
 	}
-	if top.TextStyle == nil {
+
+	// If top cell does not override the text style, then fallback to the bottom cell's, unless it is hidden by an opaque background
+	if top.TextStyle == nil && (top.hasInk() || top.Background.A != 0xFF) {
 		result.TextStyle = bottom.TextStyle
 	}
 
@@ -44,6 +94,9 @@ func (bottom Cell) Blend(top Cell) Cell {
 type Canvas struct {
 	size  Size
 	cells []Cell
+
+	// Stack of clip rects, where the last is the intersection of all the others
+	clipStack []Rect
 }
 
 func NewCanvas(size Size) Canvas {
@@ -61,56 +114,168 @@ func (c *Canvas) Size() Size {
 	return c.size
 }
 
+// Returns the Rect covering the whole canvas
+func (c *Canvas) Bounds() Rect {
+	return RectXYWH(0, 0, c.size.Width.Int(), c.size.Height.Int())
+}
+
+// Returns the Rect that drawing operations are currently limited to
+func (c *Canvas) ClipRect() Rect {
+	if len(c.clipStack) == 0 {
+		return c.Bounds()
+	}
+
+	return c.clipStack[len(c.clipStack)-1]
+}
+
+// Limits all following drawing operations to the intersection of the given rect and the current clip rect, until PopClip is called
+func (c *Canvas) PushClip(x, y, width, height int) {
+	c.clipStack = append(c.clipStack, c.ClipRect().Intersect(RectXYWH(x, y, width, height)))
+}
+
+// Restores the clip rect that was active before the last PushClip
+func (c *Canvas) PopClip() {
+	if len(c.clipStack) == 0 {
+		panic("PopClip() called without a matching PushClip()")
+	}
+
+	c.clipStack = c.clipStack[:len(c.clipStack)-1]
+}
+
+// Returns the cell at the given position, or an empty cell if the position is outside of the canvas
 func (c *Canvas) GetCell(x, y int) Cell {
+	if !c.Bounds().Contains(Pos{X: x, Y: y}) {
+		return Cell{}
+	}
+
 	return c.cells[y*c.size.Width.Int()+x]
 }
 
+// Replaces the cell at the given position, doing nothing if the position is outside of the clip rect.
+// A wide grapheme also covers the cell to its right, and is replaced by a space if that cell is outside of the clip rect.
 func (c *Canvas) SetCell(x, y int, cell Cell) {
+	clip := c.ClipRect()
+	if !clip.Contains(Pos{X: x, Y: y}) {
+		return
+	}
+
+	if cell.Grapheme != "" && cell.Width == 0 {
+		cell.Width = GraphemeWidth(cell.Grapheme)
+	}
+	if cell.Width == 2 && !clip.Contains(Pos{X: x + 1, Y: y}) {
+		cell.Grapheme = " "
+		cell.Width = 1
+	}
+
 	c.cells[y*c.size.Width.Int()+x] = cell
+	if cell.Width == 2 {
+		c.cells[y*c.size.Width.Int()+x+1] = Cell{
+			Continuation: true,
+			Background:   cell.Background,
+			Foreground:   cell.Foreground,
+			TextStyle:    cell.TextStyle,
+		}
+	}
+
+	c.repairWideCells(y, x-1, x+cell.Width+1)
+}
+
+// Replaces any wide grapheme that lost its continuation cell, and any continuation cell that lost its wide grapheme, with a space.
+// Only cells in row y between columns from (inclusive) and to (exclusive) are checked.
+func (c *Canvas) repairWideCells(y, from, to int) {
+	width := c.size.Width.Int()
+	row := c.cells[y*width : (y+1)*width]
+
+	for x := max(from, 0); x < min(to, width); x++ {
+		cell := &row[x]
+
+		if cell.Continuation {
+			if x == 0 || row[x-1].Continuation || row[x-1].Width != 2 {
+				cell.Grapheme = " "
+				cell.Width = 1
+				cell.Continuation = false
+			}
+		} else if cell.Width == 2 {
+			if x+1 == width || !row[x+1].Continuation {
+				cell.Grapheme = " "
+				cell.Width = 1
+			}
+		}
+	}
 }
 
 func (c *Canvas) FillBackground(x, y, width, height int, background Color) {
-	for i := y; i < y+height; i++ {
-		for j := x; j < x+width; j++ {
+	area := c.ClipRect().Intersect(RectXYWH(x, y, width, height))
+
+	for i := area.Min.Y; i < area.Max.Y; i++ {
+		for j := area.Min.X; j < area.Max.X; j++ {
 			cell := &c.cells[i*c.size.Width.Int()+j]
 			cell.Background = background
 		}
 	}
 }
 
+// Replaces every cell within the clip rect with the result of calling fn on it
+func (c *Canvas) TransformCells(fn func(x, y int, cell Cell) Cell) {
+	area := c.ClipRect()
+
+	for i := area.Min.Y; i < area.Max.Y; i++ {
+		for j := area.Min.X; j < area.Max.X; j++ {
+			cell := &c.cells[i*c.size.Width.Int()+j]
+			*cell = fn(j, i, *cell)
+		}
+
+		c.repairWideCells(i, area.Min.X-1, area.Max.X+1)
+	}
+}
+
+// Blends the top canvas onto this canvas at the given position. Any part of the top canvas outside of the clip rect is discarded.
 func (c *Canvas) OverlayCanvas(x, y int, topCanvas Canvas) {
-	topCanvasIndex := 0
-	for i := y; i < y+topCanvas.size.Height.Int(); i++ {
-		for j := x; j < x+topCanvas.size.Width.Int(); j++ {
-			bottomCell := &c.cells[i*c.size.Width.Int()+j]
-			topCell := topCanvas.cells[topCanvasIndex]
+	c.overlayCanvas(x, y, topCanvas, false)
+}
 
-			*bottomCell = bottomCell.Blend(topCell)
+// Like OverlayCanvas, but box drawing lines of the top canvas are joined with the ones they are drawn over, so that borders meet with the right junctions.
+// A line hidden by an opaque background is not joined.
+func (c *Canvas) OverlayCanvasJoiningLines(x, y int, topCanvas Canvas) {
+	c.overlayCanvas(x, y, topCanvas, true)
+}
 
-			topCanvasIndex += 1
+func (c *Canvas) overlayCanvas(x, y int, topCanvas Canvas, joinLines bool) {
+	topWidth := topCanvas.size.Width.Int()
+	area := c.ClipRect().Intersect(RectXYWH(x, y, topWidth, topCanvas.size.Height.Int()))
+
+	for i := area.Min.Y; i < area.Max.Y; i++ {
+		for j := area.Min.X; j < area.Max.X; j++ {
+			bottomCell := &c.cells[i*c.size.Width.Int()+j]
+			topCell := topCanvas.cells[(i-y)*topWidth+(j-x)]
+
+			result := bottomCell.Blend(topCell)
+			if joinLines && topCell.Background.A != 0xFF {
+				if joined, ok := joinBoxDrawing(bottomCell.Grapheme, topCell.Grapheme); ok {
+					result.Grapheme = joined
+				}
+			}
+			*bottomCell = result
 		}
+
+		c.repairWideCells(i, area.Min.X-1, area.Max.X+1)
 	}
 }
 
+// Draws the image onto this canvas at the given position, with each pixel taking up one cell. Any part of the image outside of the clip rect is discarded.
 func (c *Canvas) OverlayImage(x, y int, image image.Image) {
 	imageBound := image.Bounds()
-	imageWidth := imageBound.Dx()
-	imageHeight := imageBound.Dy()
-
-	imageX := image.Bounds().Min.X
-	imageY := image.Bounds().Min.Y
+	area := c.ClipRect().Intersect(RectXYWH(x, y, imageBound.Dx(), imageBound.Dy()))
 
-	for i := y; i < y+imageHeight; i++ {
-		for j := x; j < x+imageWidth; j++ {
+	for i := area.Min.Y; i < area.Max.Y; i++ {
+		for j := area.Min.X; j < area.Max.X; j++ {
 			c.cells[i*c.size.Width.Int()+j] = Cell{
-				Rune:       ' ',
-				Background: ColorFromImageColor(image.At(imageX, imageY)),
+				Grapheme:   " ",
+				Width:      1,
+				Background: ColorFromImageColor(image.At(imageBound.Min.X+j-x, imageBound.Min.Y+i-y)),
 			}
-
-			imageX++
 		}
 
-		imageX = image.Bounds().Min.X
-		imageY++
+		c.repairWideCells(i, area.Min.X-1, area.Max.X+1)
 	}
 }
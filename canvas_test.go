@@ -0,0 +1,267 @@
+package goat
+
+import (
+	"slices"
+	"testing"
+)
+
+// Describes each cell of the first row of the canvas, with continuation cells shown as ">"
+func rowGraphemes(c *Canvas) []string {
+	var row []string
+	for x := range c.Size().Width.Int() {
+		cell := c.GetCell(x, 0)
+		if cell.Continuation {
+			row = append(row, ">")
+		} else {
+			row = append(row, cell.Grapheme)
+		}
+	}
+	return row
+}
+
+func TestCanvasSetCell(t *testing.T) {
+	type setCell struct {
+		x    int
+		cell Cell
+	}
+
+	tests := []struct {
+		name string
+		// Limits drawing to the columns from clip[0] (inclusive) to clip[1] (exclusive) when set
+		clip  []int
+		cells []setCell
+		want  []string
+	}{
+		{
+			name:  "narrow grapheme",
+			cells: []setCell{{1, Cell{Grapheme: "a"}}},
+			want:  []string{"", "a", "", ""},
+		},
+		{
+			name:  "wide grapheme covers the next cell",
+			cells: []setCell{{1, Cell{Grapheme: "世"}}},
+			want:  []string{"", "世", ">", ""},
+		},
+		{
+			name:  "wide emoji covers the next cell",
+			cells: []setCell{{0, Cell{Grapheme: "👍🏽"}}},
+			want:  []string{"👍🏽", ">", "", ""},
+		},
+		{
+			name:  "wide grapheme in the last column is replaced by a space",
+			cells: []setCell{{3, Cell{Grapheme: "世"}}},
+			want:  []string{"", "", "", " "},
+		},
+		{
+			name:  "wide grapheme cut off by the clip rect is replaced by a space",
+			clip:  []int{0, 2},
+			cells: []setCell{{1, Cell{Grapheme: "世"}}},
+			want:  []string{"", " ", "", ""},
+		},
+		{
+			name:  "cell outside of the clip rect is ignored",
+			clip:  []int{1, 3},
+			cells: []setCell{{0, Cell{Grapheme: "a"}}, {3, Cell{Grapheme: "b"}}},
+			want:  []string{"", "", "", ""},
+		},
+		{
+			name:  "overwriting a continuation removes its wide grapheme",
+			cells: []setCell{{0, Cell{Grapheme: "世"}}, {1, Cell{Grapheme: "a"}}},
+			want:  []string{" ", "a", "", ""},
+		},
+		{
+			name:  "overwriting a wide grapheme removes its continuation",
+			cells: []setCell{{0, Cell{Grapheme: "世"}}, {0, Cell{Grapheme: "a"}}},
+			want:  []string{"a", " ", "", ""},
+		},
+		{
+			name:  "overlapping wide graphemes",
+			cells: []setCell{{0, Cell{Grapheme: "世"}}, {1, Cell{Grapheme: "界"}}},
+			want:  []string{" ", "界", ">", ""},
+		},
+		{
+			name:  "explicit width is kept",
+			cells: []setCell{{0, Cell{Grapheme: "a", Width: 2}}},
+			want:  []string{"a", ">", "", ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canvas := NewCanvas(SizeInt(4, 1))
+			if test.clip != nil {
+				canvas.PushClip(test.clip[0], 0, test.clip[1]-test.clip[0], 1)
+			}
+
+			for _, set := range test.cells {
+				canvas.SetCell(set.x, 0, set.cell)
+			}
+
+			if got := rowGraphemes(&canvas); !slices.Equal(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCanvasRepairWideCells(t *testing.T) {
+	wide := Cell{Grapheme: "世", Width: 2}
+	continuation := Cell{Continuation: true}
+	narrow := Cell{Grapheme: "a", Width: 1}
+
+	tests := []struct {
+		name     string
+		row      []Cell
+		from, to int
+		want     []string
+	}{
+		{
+			name: "intact wide grapheme is kept",
+			row:  []Cell{wide, continuation, narrow},
+			from: 0, to: 3,
+			want: []string{"世", ">", "a"},
+		},
+		{
+			name: "wide grapheme without a continuation",
+			row:  []Cell{wide, narrow, narrow},
+			from: 0, to: 3,
+			want: []string{" ", "a", "a"},
+		},
+		{
+			name: "continuation without a wide grapheme",
+			row:  []Cell{narrow, continuation, narrow},
+			from: 0, to: 3,
+			want: []string{"a", " ", "a"},
+		},
+		{
+			name: "continuation in the first column",
+			row:  []Cell{continuation, narrow, narrow},
+			from: 0, to: 3,
+			want: []string{" ", "a", "a"},
+		},
+		{
+			name: "wide grapheme in the last column",
+			row:  []Cell{narrow, narrow, wide},
+			from: 0, to: 3,
+			want: []string{"a", "a", " "},
+		},
+		{
+			name: "cells outside of the range are left alone",
+			row:  []Cell{wide, narrow, continuation},
+			from: 2, to: 3,
+			want: []string{"世", "a", " "},
+		},
+		{
+			name: "range past the edges of the row",
+			row:  []Cell{continuation, narrow, wide},
+			from: -1, to: 4,
+			want: []string{" ", "a", " "},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canvas := NewCanvas(SizeInt(len(test.row), 1))
+			copy(canvas.cells, test.row)
+
+			canvas.repairWideCells(0, test.from, test.to)
+
+			if got := rowGraphemes(&canvas); !slices.Equal(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCellBlend(t *testing.T) {
+	red := ColorRGB(0xff, 0, 0)
+	green := ColorRGB(0, 0xff, 0)
+	blue := ColorRGB(0, 0, 0xff)
+	black := ColorRGB(0, 0, 0)
+	translucentWhite := Color{0xff, 0xff, 0xff, 0x80}
+	translucentBlue := Color{0, 0, 0xff, 0x80}
+	bold := &CellTextStyle{Bold: true}
+	italic := &CellTextStyle{Italic: true}
+	underline := &CellTextStyle{Underline: true}
+
+	tests := []struct {
+		name        string
+		bottom, top Cell
+		want        Cell
+	}{
+		{
+			name:   "top grapheme replaces the bottom one",
+			bottom: Cell{Grapheme: "a", Width: 1, Background: red, TextStyle: bold},
+			top:    Cell{Grapheme: "b", Width: 1, Foreground: green},
+			want:   Cell{Grapheme: "b", Width: 1, Background: red, Foreground: green, TextStyle: bold},
+		},
+		{
+			name:   "top text style replaces the bottom one",
+			bottom: Cell{Grapheme: "a", Width: 1, TextStyle: bold},
+			top:    Cell{Grapheme: "b", Width: 1, TextStyle: italic},
+			want:   Cell{Grapheme: "b", Width: 1, TextStyle: italic},
+		},
+		{
+			name:   "wide top grapheme",
+			bottom: Cell{Grapheme: "a", Width: 1},
+			top:    Cell{Grapheme: "世", Width: 2},
+			want:   Cell{Grapheme: "世", Width: 2},
+		},
+		{
+			name:   "underlined space is drawn",
+			bottom: Cell{Grapheme: "a", Width: 1},
+			top:    Cell{Grapheme: " ", Width: 1, TextStyle: underline},
+			want:   Cell{Grapheme: " ", Width: 1, TextStyle: underline},
+		},
+		{
+			name:   "translucent foreground is blended with an opaque background",
+			bottom: Cell{Background: blue},
+			top:    Cell{Grapheme: "b", Width: 1, Foreground: translucentWhite},
+			want:   Cell{Grapheme: "b", Width: 1, Background: blue, Foreground: Color{0x80, 0x80, 0xff, 0xff}},
+		},
+		{
+			name:   "translucent foreground is kept over a translucent background",
+			bottom: Cell{},
+			top:    Cell{Grapheme: "b", Width: 1, Foreground: translucentWhite},
+			want:   Cell{Grapheme: "b", Width: 1, Foreground: translucentWhite},
+		},
+		{
+			name:   "opaque background hides the bottom cell",
+			bottom: Cell{Grapheme: "a", Width: 1, Foreground: red, TextStyle: bold},
+			top:    Cell{Background: blue},
+			want:   Cell{Grapheme: " ", Width: 1, Background: blue},
+		},
+		{
+			name:   "translucent background tints the bottom grapheme",
+			bottom: Cell{Grapheme: "a", Width: 1, Foreground: red, Background: black, TextStyle: bold},
+			top:    Cell{Background: translucentBlue},
+			want:   Cell{Grapheme: "a", Width: 1, Foreground: Color{0x7f, 0, 0x80, 0xff}, Background: Color{0, 0, 0x80, 0xff}, TextStyle: bold},
+		},
+		{
+			name:   "translucent background tints the default foreground",
+			bottom: Cell{Grapheme: "a", Width: 1},
+			top:    Cell{Background: translucentBlue},
+			want:   Cell{Grapheme: "a", Width: 1, Foreground: Color{0x66, 0x66, 0xe6, 0xff}, Background: translucentBlue},
+		},
+		{
+			name:   "empty top cell shows the bottom wide grapheme",
+			bottom: Cell{Grapheme: "世", Width: 2, Background: red},
+			top:    Cell{},
+			want:   Cell{Grapheme: "世", Width: 2, Background: red},
+		},
+		{
+			name:   "empty top cell shows the bottom continuation",
+			bottom: Cell{Continuation: true, Background: red},
+			top:    Cell{},
+			want:   Cell{Continuation: true, Background: red},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.bottom.Blend(test.top); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package goat
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// A place to read and write copied text, other than the terminal
+type ClipboardProvider interface {
+	Read() (string, error)
+	Write(text string) error
+}
+
+// Keeps copied text in memory, so it is only available within this app
+type MemoryClipboardProvider struct {
+	lock sync.Mutex
+	text string
+}
+
+var _ ClipboardProvider = &MemoryClipboardProvider{}
+
+func (p *MemoryClipboardProvider) Read() (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.text, nil
+}
+
+func (p *MemoryClipboardProvider) Write(text string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.text = text
+	return nil
+}
+
+// Runs external commands to access the clipboard, such as pbcopy and pbpaste on macOS
+type CommandClipboardProvider struct {
+	// Command and arguments that print the clipboard contents
+	ReadCommand []string
+	// Command and arguments that replace the clipboard contents with their standard input
+	WriteCommand []string
+}
+
+var _ ClipboardProvider = CommandClipboardProvider{}
+
+func (p CommandClipboardProvider) Read() (string, error) {
+	if len(p.ReadCommand) == 0 {
+		return "", errors.New("clipboard read command is empty")
+	}
+
+	output, err := exec.Command(p.ReadCommand[0], p.ReadCommand[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("clipboard read command failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (p CommandClipboardProvider) Write(text string) error {
+	if len(p.WriteCommand) == 0 {
+		return errors.New("clipboard write command is empty")
+	}
+
+	cmd := exec.Command(p.WriteCommand[0], p.WriteCommand[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("clipboard write command failed: %w", err)
+	}
+
+	return nil
+}
+
+// Returns a provider for the clipboard of the local desktop, or a MemoryClipboardProvider if no clipboard command is available
+func DetectClipboardProvider() ClipboardProvider {
+	candidates := []CommandClipboardProvider{}
+
+	switch {
+	case runtime.GOOS == "darwin":
+		candidates = append(candidates, CommandClipboardProvider{
+			ReadCommand:  []string{"pbpaste"},
+			WriteCommand: []string{"pbcopy"},
+		})
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		candidates = append(candidates, CommandClipboardProvider{
+			ReadCommand:  []string{"wl-paste", "--no-newline"},
+			WriteCommand: []string{"wl-copy"},
+		})
+	}
+
+	if os.Getenv("DISPLAY") != "" {
+		candidates = append(candidates,
+			CommandClipboardProvider{
+				ReadCommand:  []string{"xclip", "-selection", "clipboard", "-out"},
+				WriteCommand: []string{"xclip", "-selection", "clipboard", "-in"},
+			},
+			CommandClipboardProvider{
+				ReadCommand:  []string{"xsel", "--clipboard", "--output"},
+				WriteCommand: []string{"xsel", "--clipboard", "--input"},
+			},
+		)
+	}
+
+	for _, candidate := range candidates {
+		_, readErr := exec.LookPath(candidate.ReadCommand[0])
+		_, writeErr := exec.LookPath(candidate.WriteCommand[0])
+		if readErr == nil && writeErr == nil {
+			return candidate
+		}
+	}
+
+	return &MemoryClipboardProvider{}
+}
+
+// Copies and pastes text, both through the terminal using OSC 52 escape sequences (which works over SSH), and through a local provider.
+//
+// Terminals do not reliably answer requests to read the clipboard, so reading only uses the local provider.
+type SystemClipboard struct {
+	lock     sync.Mutex
+	provider ClipboardProvider
+	// Terminal of the running app, which OSC 52 sequences are written to while holding terminalLock, so they are not mixed into the app's drawing
+	terminal     io.Writer
+	terminalLock *sync.Mutex
+	listeners    map[*Element]func(text string)
+}
+
+// The clipboard shared by the whole app
+var Clipboard = &SystemClipboard{
+	listeners: make(map[*Element]func(text string)),
+}
+
+// Replaces the provider used for reading, and for writing alongside the terminal.
+// By default, the provider is chosen by DetectClipboardProvider when the clipboard is first used.
+func (c *SystemClipboard) SetProvider(provider ClipboardProvider) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.provider = provider
+}
+
+func (c *SystemClipboard) getProvider() ClipboardProvider {
+	if c.provider == nil {
+		c.provider = DetectClipboardProvider()
+	}
+	return c.provider
+}
+
+func (c *SystemClipboard) Read() (string, error) {
+	c.lock.Lock()
+	provider := c.getProvider()
+	c.lock.Unlock()
+
+	return provider.Read()
+}
+
+// Copies text to the terminal's clipboard and the provider, only failing if neither could be written to
+func (c *SystemClipboard) Write(text string) error {
+	c.lock.Lock()
+	provider := c.getProvider()
+	terminal, terminalLock := c.terminal, c.terminalLock
+	listeners := make([]func(text string), 0, len(c.listeners))
+	for _, listener := range c.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.lock.Unlock()
+
+	var terminalErr error
+	if terminal != nil {
+		terminalLock.Lock()
+		terminalErr = writeOsc52(terminal, text)
+		terminalLock.Unlock()
+	} else {
+		terminalErr = errors.New("no app is running in the terminal")
+	}
+
+	providerErr := provider.Write(text)
+	if terminalErr != nil && providerErr != nil {
+		return fmt.Errorf("unable to write to clipboard: %w", errors.Join(terminalErr, providerErr))
+	}
+
+	for _, listener := range listeners {
+		listener(text)
+	}
+
+	return nil
+}
+
+// Sets the terminal that OSC 52 sequences are written to, or stops writing them when nil
+func (c *SystemClipboard) setTerminal(terminal io.Writer, terminalLock *sync.Mutex) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.terminal = terminal
+	c.terminalLock = terminalLock
+}
+
+// Sets the clipboard of the terminal emulator, which passes through SSH and, when wrapped, tmux
+func writeOsc52(terminal io.Writer, text string) error {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if os.Getenv("TMUX") != "" {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	_, err := io.WriteString(terminal, sequence)
+	return err
+}
+
+// A hook that returns the text most recently copied within this app, and a function to copy text to the clipboard.
+// The widget rerenders whenever text is copied, for example to show what a paste would insert.
+func UseClipboard() (string, func(text string) error) {
+	context := getHookContext()
+	curElement := context.element
+
+	copied, setCopied := UseState("")
+
+	UseEffect(func() func() {
+		Clipboard.lock.Lock()
+		Clipboard.listeners[curElement] = setCopied
+		Clipboard.lock.Unlock()
+
+		return func() {
+			Clipboard.lock.Lock()
+			delete(Clipboard.listeners, curElement)
+			Clipboard.lock.Unlock()
+		}
+	}, []any{})
+
+	return copied, Clipboard.Write
+}
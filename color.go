@@ -3,12 +3,20 @@ package goat
 import (
 	"fmt"
 	imageColor "image/color"
+	"math"
 )
 
 type Color struct {
 	R, G, B, A uint8
 }
 
+// Colors assumed to be the terminal's default foreground and background, which translucent colors are blended with when drawn, since the terminal cannot be asked for them.
+// Change these to match the terminal's theme, such as for one with a light background.
+var (
+	DefaultForeground = ColorRGB(0xcc, 0xcc, 0xcc)
+	DefaultBackground = ColorRGB(0x00, 0x00, 0x00)
+)
+
 func (c Color) String() string {
 	return fmt.Sprintf("(%d,%d,%d,%d)", c.R, c.G, c.B, c.A)
 }
@@ -22,6 +30,7 @@ func ColorFromImageColor(c imageColor.Color) Color {
 	return Color(nrgba)
 }
 
+// Returns the color seen when the top color is drawn over this one, using the alpha of both
 func (bottom Color) Blend(top Color) Color {
 	// Shortcut if top color is fully opaque or fully transparent
 	switch top.A {
@@ -32,27 +41,239 @@ func (bottom Color) Blend(top Color) Color {
 	}
 
 	var (
-		topR = uint16(top.R)
-		topG = uint16(top.G)
-		topB = uint16(top.B)
-		topA = uint16(top.A)
-
-		bottomR = uint16(bottom.R)
-		bottomG = uint16(bottom.G)
-		bottomB = uint16(bottom.B)
-		bottomA = uint16(bottom.A)
+		topA    = uint32(top.A)
+		bottomA = uint32(bottom.A)
 	)
 
-	resultA := topA + bottomA/0xff*(0xff-topA)
+	// Alpha of the result, scaled up by 0xff to keep the precision of the bottom color's contribution
+	resultA := topA*0xff + bottomA*(0xff-topA)
 
-	blendChannel := func(bottomC, topC uint16) uint8 {
-		return uint8((topC*topA + bottomC*bottomA/0xff*(0xff-topA)) / resultA)
+	blendChannel := func(bottomC, topC uint8) uint8 {
+		return uint8((uint32(topC)*topA*0xff + uint32(bottomC)*bottomA*(0xff-topA) + resultA/2) / resultA)
 	}
 
 	return Color{
-		blendChannel(bottomR, topR),
-		blendChannel(bottomG, topG),
-		blendChannel(bottomB, topB),
-		uint8(resultA),
+		blendChannel(bottom.R, top.R),
+		blendChannel(bottom.G, top.G),
+		blendChannel(bottom.B, top.B),
+		uint8((resultA + 0xff/2) / 0xff),
+	}
+}
+
+// Converts an sRGB channel to linear light, from 0 to 1
+func linearChannel(c uint8) float64 {
+	v := float64(c) / 0xff
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// Returns the color in the Oklab color space, ignoring alpha
+func (c Color) oklab() (l, a, b float64) {
+	r, g, bl := linearChannel(c.R), linearChannel(c.G), linearChannel(c.B)
+
+	lms1 := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	lms2 := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	lms3 := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	return 0.2104542553*lms1 + 0.7936177850*lms2 - 0.0040720468*lms3,
+		1.9779984951*lms1 - 2.4285922050*lms2 + 0.4505937099*lms3,
+		0.0259040371*lms1 + 0.7827717662*lms2 - 0.8086757660*lms3
+}
+
+// Converts linear light, from 0 to 1, to an sRGB channel
+func gammaChannel(v float64) uint8 {
+	if v <= 0.0031308 {
+		return unitToChannel(v * 12.92)
+	}
+	return unitToChannel(1.055*math.Pow(v, 1/2.4) - 0.055)
+}
+
+// Returns the linear sRGB of a color in the Oklab color space, which may be outside of the range 0 to 1 if the color cannot be shown in sRGB
+func oklabToLinear(l, a, b float64) (r, g, bl float64) {
+	lms1 := l + 0.3963377774*a + 0.2158037573*b
+	lms2 := l - 0.1055613458*a - 0.0638541728*b
+	lms3 := l - 0.0894841775*a - 1.2914855480*b
+	lms1, lms2, lms3 = lms1*lms1*lms1, lms2*lms2*lms2, lms3*lms3*lms3
+
+	return 4.0767416621*lms1 - 3.3077115913*lms2 + 0.2309699292*lms3,
+		-1.2684380046*lms1 + 2.6097574011*lms2 - 0.3413193965*lms3,
+		-0.0041960863*lms1 - 0.7034186147*lms2 + 1.7076147010*lms3
+}
+
+// Returns an opaque color from its hue in degrees, and its saturation and lightness from 0 to 1
+func ColorHSL(h, s, l float64) Color {
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+	s = min(max(s, 0), 1)
+	l = min(max(l, 0), 1)
+
+	channel := func(n float64) uint8 {
+		k := math.Mod(n+h/30, 12)
+		return unitToChannel(l - s*min(l, 1-l)*max(-1, min(k-3, 9-k, 1)))
+	}
+
+	return ColorRGB(channel(0), channel(8), channel(4))
+}
+
+// Returns the hue of the color in degrees, and its saturation and lightness from 0 to 1, ignoring alpha
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff
+	maxC, minC := max(r, g, b), min(r, g, b)
+	l = (maxC + minC) / 2
+
+	delta := maxC - minC
+	if delta == 0 {
+		return 0, 0, l
+	}
+
+	s = delta / (1 - math.Abs(2*l-1))
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/delta+6, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	return h * 60, s, l
+}
+
+// Returns an opaque color from its lightness from 0 to 1, chroma, and hue in degrees in the OKLCH color space.
+// Colors that sRGB cannot show have their chroma reduced until they can be shown, which keeps their lightness and hue.
+func ColorOKLCH(l, c, h float64) Color {
+	l = min(max(l, 0), 1)
+	c = max(c, 0)
+	hRad := h * math.Pi / 180
+
+	inGamut := func(c float64) bool {
+		r, g, b := oklabToLinear(l, c*math.Cos(hRad), c*math.Sin(hRad))
+		const epsilon = 1e-6
+		return min(r, g, b) >= -epsilon && max(r, g, b) <= 1+epsilon
+	}
+
+	if !inGamut(c) {
+		low, high := 0.0, c
+		for i := 0; i < 24; i++ {
+			mid := (low + high) / 2
+			if inGamut(mid) {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		c = low
+	}
+
+	r, g, b := oklabToLinear(l, c*math.Cos(hRad), c*math.Sin(hRad))
+	return ColorRGB(gammaChannel(r), gammaChannel(g), gammaChannel(b))
+}
+
+// Returns the lightness of the color from 0 to 1, its chroma, and its hue in degrees in the OKLCH color space, ignoring alpha
+func (c Color) OKLCH() (l, chroma, h float64) {
+	l, a, b := c.oklab()
+	chroma = math.Hypot(a, b)
+	h = math.Mod(math.Atan2(b, a)*180/math.Pi+360, 360)
+	return l, chroma, h
+}
+
+// Returns the color with its OKLCH lightness raised by the amount, which ranges from 0 for black to 1 for white
+func (c Color) Lighten(amount float64) Color {
+	l, chroma, h := c.OKLCH()
+	return ColorOKLCH(l+amount, chroma, h).WithAlpha(c.A)
+}
+
+// Returns the color with its OKLCH lightness lowered by the amount, which ranges from 0 for black to 1 for white
+func (c Color) Darken(amount float64) Color {
+	return c.Lighten(-amount)
+}
+
+// Returns a mix of the colors, from this color at 0 to the other at 1, with t clamped between the two.
+// The colors are mixed in Oklab, which keeps mixes from looking muddy or darker than either color.
+func (c Color) Mix(other Color, t float64) Color {
+	t = min(max(t, 0), 1)
+	l1, a1, b1 := c.oklab()
+	l2, a2, b2 := other.oklab()
+
+	r, g, b := oklabToLinear(l1+(l2-l1)*t, a1+(a2-a1)*t, b1+(b2-b1)*t)
+	return Color{
+		R: gammaChannel(r),
+		G: gammaChannel(g),
+		B: gammaChannel(b),
+		A: uint8(math.Round(float64(c.A) + (float64(other.A)-float64(c.A))*t)),
+	}
+}
+
+// Returns the color with its alpha replaced
+func (c Color) WithAlpha(alpha uint8) Color {
+	c.A = alpha
+	return c
+}
+
+// Returns the relative luminance of the color as defined by WCAG, from 0 for black to 1 for white, ignoring alpha
+func (c Color) Luminance() float64 {
+	return 0.2126*linearChannel(c.R) + 0.7152*linearChannel(c.G) + 0.0722*linearChannel(c.B)
+}
+
+// Minimum contrast ratios between text and its background required by WCAG
+const (
+	ContrastRatioAA  = 4.5
+	ContrastRatioAAA = 7.0
+)
+
+// Returns the WCAG contrast ratio between the colors, from 1 for no contrast to 21 for black on white, ignoring alpha
+func (c Color) ContrastRatio(other Color) float64 {
+	l1, l2 := c.Luminance(), other.Luminance()
+	return (max(l1, l2) + 0.05) / (min(l1, l2) + 0.05)
+}
+
+// Returns the color, lightened or darkened as little as possible so that text in it is readable on the background,
+// meeting the ContrastRatioAA required by WCAG
+func (c Color) EnsureContrast(background Color) Color {
+	return c.EnsureContrastRatio(background, ContrastRatioAA)
+}
+
+// Returns the color, lightened or darkened as little as possible to have at least the given contrast ratio with the background.
+// The hue of the color is kept where possible, but if neither lightening nor darkening it is enough, then either black or white is returned, whichever contrasts more.
+func (c Color) EnsureContrastRatio(background Color, ratio float64) Color {
+	if c.ContrastRatio(background) >= ratio {
+		return c
+	}
+
+	l, chroma, h := c.OKLCH()
+
+	// Search for the smallest change in lightness in the direction away from the background first
+	directions := []float64{1, -1}
+	if background.Luminance() > c.Luminance() {
+		directions = []float64{-1, 1}
+	}
+
+	for _, direction := range directions {
+		limit := 1.0
+		if direction < 0 {
+			limit = 0
+		}
+		if ColorOKLCH(limit, chroma, h).ContrastRatio(background) < ratio {
+			continue
+		}
+
+		near, far := l, limit
+		for i := 0; i < 24; i++ {
+			mid := (near + far) / 2
+			if ColorOKLCH(mid, chroma, h).ContrastRatio(background) >= ratio {
+				far = mid
+			} else {
+				near = mid
+			}
+		}
+
+		return ColorOKLCH(far, chroma, h).WithAlpha(c.A)
+	}
+
+	black, white := ColorRGB(0, 0, 0), ColorRGB(0xff, 0xff, 0xff)
+	if black.ContrastRatio(background) > white.ContrastRatio(background) {
+		return black.WithAlpha(c.A)
 	}
+	return white.WithAlpha(c.A)
 }
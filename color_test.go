@@ -0,0 +1,113 @@
+package goat
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColorHSL(t *testing.T) {
+	tests := []struct {
+		name    string
+		h, s, l float64
+		want    Color
+	}{
+		{name: "red", h: 0, s: 1, l: 0.5, want: ColorRGB(0xff, 0x00, 0x00)},
+		{name: "green", h: 120, s: 1, l: 0.5, want: ColorRGB(0x00, 0xff, 0x00)},
+		{name: "blue", h: 240, s: 1, l: 0.5, want: ColorRGB(0x00, 0x00, 0xff)},
+		{name: "orange", h: 32, s: 1, l: 0.5, want: ColorRGB(0xff, 0x88, 0x00)},
+		{name: "gray without saturation", h: 90, s: 0, l: 0.5, want: ColorRGB(0x80, 0x80, 0x80)},
+		{name: "negative hue wraps around", h: -120, s: 1, l: 0.5, want: ColorRGB(0x00, 0x00, 0xff)},
+		{name: "hue past a full turn wraps around", h: 480, s: 1, l: 0.5, want: ColorRGB(0x00, 0xff, 0x00)},
+		{name: "saturation is clamped", h: 0, s: 2, l: 0.5, want: ColorRGB(0xff, 0x00, 0x00)},
+		{name: "lightness is clamped", h: 0, s: 1, l: 1.5, want: ColorRGB(0xff, 0xff, 0xff)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ColorHSL(test.h, test.s, test.l); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestColorToHSL(t *testing.T) {
+	tests := []struct {
+		color   Color
+		h, s, l float64
+	}{
+		{color: ColorRGB(0xff, 0x00, 0x00), h: 0, s: 1, l: 0.5},
+		{color: ColorRGB(0x00, 0xff, 0x00), h: 120, s: 1, l: 0.5},
+		{color: ColorRGB(0x00, 0x00, 0xff), h: 240, s: 1, l: 0.5},
+		{color: ColorRGB(0xff, 0x00, 0xff), h: 300, s: 1, l: 0.5},
+		{color: ColorRGB(0xff, 0xff, 0xff), h: 0, s: 0, l: 1},
+		{color: ColorRGB(0x00, 0x00, 0x00), h: 0, s: 0, l: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.color.String(), func(t *testing.T) {
+			h, s, l := test.color.HSL()
+			if math.Abs(h-test.h) > 1e-9 || math.Abs(s-test.s) > 1e-9 || math.Abs(l-test.l) > 1e-9 {
+				t.Errorf("got %v, %v, %v, want %v, %v, %v", h, s, l, test.h, test.s, test.l)
+			}
+
+			if got := ColorHSL(h, s, l); got != test.color {
+				t.Errorf("round trip got %v", got)
+			}
+		})
+	}
+}
+
+func TestColorOKLCH(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    Color
+		l, c, h  float64
+		hasNoHue bool
+	}{
+		{name: "white", color: ColorRGB(0xff, 0xff, 0xff), l: 1, c: 0, hasNoHue: true},
+		{name: "black", color: ColorRGB(0x00, 0x00, 0x00), l: 0, c: 0, hasNoHue: true},
+		{name: "red", color: ColorRGB(0xff, 0x00, 0x00), l: 0.628, c: 0.258, h: 29.2},
+		{name: "green", color: ColorRGB(0x00, 0xff, 0x00), l: 0.866, c: 0.295, h: 142.5},
+		{name: "blue", color: ColorRGB(0x00, 0x00, 0xff), l: 0.452, c: 0.313, h: 264.1},
+		{name: "orange", color: ColorRGB(0xff, 0x88, 0x00), l: 0.744, c: 0.181, h: 56.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, c, h := test.color.OKLCH()
+			if math.Abs(l-test.l) > 1e-3 || math.Abs(c-test.c) > 1e-3 || (!test.hasNoHue && math.Abs(h-test.h) > 0.1) {
+				t.Errorf("got %v, %v, %v, want %v, %v, %v", l, c, h, test.l, test.c, test.h)
+			}
+
+			if got := ColorOKLCH(l, c, h); got != test.color {
+				t.Errorf("round trip got %v", got)
+			}
+		})
+	}
+}
+
+func TestColorOKLCHOutOfGamut(t *testing.T) {
+	tests := []struct {
+		name    string
+		l, c, h float64
+	}{
+		{name: "vivid green", l: 0.7, c: 0.5, h: 150},
+		{name: "vivid blue", l: 0.5, c: 0.4, h: 260},
+		{name: "light magenta", l: 0.9, c: 0.3, h: 330},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, c, h := ColorOKLCH(test.l, test.c, test.h).OKLCH()
+
+			// The chroma is reduced to fit in sRGB, but the lightness and hue are kept, within the rounding of each channel
+			if c >= test.c {
+				t.Errorf("chroma %v was not reduced from %v", c, test.c)
+			}
+			if math.Abs(l-test.l) > 0.01 || math.Abs(h-test.h) > 2 {
+				t.Errorf("got lightness %v and hue %v, want %v and %v", l, h, test.l, test.h)
+			}
+		})
+	}
+}
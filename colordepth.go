@@ -0,0 +1,196 @@
+package goat
+
+import (
+	"math"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The number of colors a terminal can display
+type ColorDepth int
+
+const (
+	// Detects the color depth of the terminal
+	ColorDepthAuto ColorDepth = iota
+	// Only the terminal's default colors, with backgrounds that are closer to the default foreground drawn in reverse video
+	ColorDepthMono
+	// The 8 basic ANSI colors, such as on the Linux console
+	ColorDepth8
+	// The 8 basic ANSI colors and their bright variants
+	ColorDepth16
+	// The xterm 256 color palette
+	ColorDepth256
+	// Any 24-bit RGB color
+	ColorDepthTrueColor
+)
+
+// Returns the color depth of the terminal.
+// NO_COLOR turns colors off, and COLORTERM set to truecolor or 24bit turns on 24-bit color, otherwise the depth is taken from the terminal's terminfo.
+func detectColorDepth(screen tcell.Screen) ColorDepth {
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorDepthMono
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorDepthTrueColor
+	}
+
+	colors := screen.Colors()
+	switch {
+	case colors >= 1<<24:
+		return ColorDepthTrueColor
+	case colors >= 256:
+		return ColorDepth256
+	case colors >= 16:
+		return ColorDepth16
+	case colors >= 8:
+		return ColorDepth8
+	default:
+		return ColorDepthMono
+	}
+}
+
+// RGB values of the xterm 256 color palette. The first 16 colors are the standard xterm ones, although most terminals let the user change them.
+var xtermPalette = func() [256]Color {
+	palette := [256]Color{
+		ColorRGB(0x00, 0x00, 0x00), ColorRGB(0x80, 0x00, 0x00), ColorRGB(0x00, 0x80, 0x00), ColorRGB(0x80, 0x80, 0x00),
+		ColorRGB(0x00, 0x00, 0x80), ColorRGB(0x80, 0x00, 0x80), ColorRGB(0x00, 0x80, 0x80), ColorRGB(0xc0, 0xc0, 0xc0),
+		ColorRGB(0x80, 0x80, 0x80), ColorRGB(0xff, 0x00, 0x00), ColorRGB(0x00, 0xff, 0x00), ColorRGB(0xff, 0xff, 0x00),
+		ColorRGB(0x00, 0x00, 0xff), ColorRGB(0xff, 0x00, 0xff), ColorRGB(0x00, 0xff, 0xff), ColorRGB(0xff, 0xff, 0xff),
+	}
+
+	cubeLevels := []uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+	for i := 0; i < 216; i++ {
+		palette[16+i] = ColorRGB(cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6])
+	}
+
+	for i := 0; i < 24; i++ {
+		gray := uint8(8 + 10*i)
+		palette[232+i] = ColorRGB(gray, gray, gray)
+	}
+
+	return palette
+}()
+
+// 4x4 Bayer matrix, giving the order cells are offset in for ordered dithering
+var bayerMatrix = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// How much more differences in chroma count for than differences in lightness and hue, when finding the nearest palette entry
+const oklabChromaWeight = 8
+
+// Converts colors to ones the terminal can display
+type colorRenderer struct {
+	depth  ColorDepth
+	dither bool
+
+	// Indexes of the palette entries that colors are matched against, and the same entries in Oklab
+	palette      []int
+	paletteOklab [][3]float64
+	// Average distance between neighbouring palette entries along each channel, which is how far dithering spreads colors
+	ditherSpread float64
+
+	cache map[Color]tcell.Color
+}
+
+func newColorRenderer(depth ColorDepth, dither bool) *colorRenderer {
+	r := &colorRenderer{
+		depth:  depth,
+		dither: dither,
+		cache:  make(map[Color]tcell.Color),
+	}
+
+	first, last := 0, -1
+	switch depth {
+	case ColorDepth8:
+		first, last = 0, 7
+		r.ditherSpread = 0x80
+	case ColorDepth16:
+		first, last = 0, 15
+		r.ditherSpread = 0x80
+	case ColorDepth256:
+		// The first 16 colors are skipped, since they are often changed by the terminal's theme
+		first, last = 16, 255
+		r.ditherSpread = 0x28
+	}
+
+	for i := first; i <= last; i++ {
+		l, a, b := xtermPalette[i].oklab()
+		r.palette = append(r.palette, i)
+		r.paletteOklab = append(r.paletteOklab, [3]float64{l, a, b})
+	}
+
+	return r
+}
+
+// Returns the terminal color for the opaque background of the cell at (x, y).
+// Only backgrounds are dithered, since dithering text makes its color change from one character to the next.
+func (r *colorRenderer) backgroundColor(color Color, x, y int) tcell.Color {
+	if r.dither && r.depth != ColorDepthMono && r.depth != ColorDepthTrueColor {
+		// Offset the color by up to half the distance between palette entries, so that gradients turn into a pattern of the entries either side
+		offset := (bayerMatrix[y%4][x%4]/16 - 0.5 + 1.0/32) * r.ditherSpread
+		ditherChannel := func(c uint8) uint8 {
+			return uint8(min(max(math.Round(float64(c)+offset), 0), 0xff))
+		}
+		color = Color{ditherChannel(color.R), ditherChannel(color.G), ditherChannel(color.B), color.A}
+	}
+
+	return r.terminalColor(color)
+}
+
+// Reports whether a cell with the opaque background is drawn in reverse video, which is how a monochrome terminal shows backgrounds closer in lightness to the default foreground than to the default background
+func (r *colorRenderer) reverseBackground(background Color) bool {
+	if r.depth != ColorDepthMono {
+		return false
+	}
+
+	l, _, _ := background.oklab()
+	foregroundL, _, _ := DefaultForeground.oklab()
+	backgroundL, _, _ := DefaultBackground.oklab()
+	return math.Abs(l-foregroundL) < math.Abs(l-backgroundL)
+}
+
+// Returns the terminal color closest to the opaque color
+func (r *colorRenderer) terminalColor(color Color) tcell.Color {
+	switch r.depth {
+	case ColorDepthMono:
+		return tcell.ColorDefault
+	case ColorDepthTrueColor:
+		return tcell.NewRGBColor(int32(color.R), int32(color.G), int32(color.B))
+	}
+
+	if terminalColor, ok := r.cache[color]; ok {
+		return terminalColor
+	}
+
+	// Find the nearest palette entry in Oklab, where distances match how different colors look.
+	// Differences in chroma count for more than differences in lightness and hue, so that grays do not turn into dull colors in small palettes.
+	l, a, b := color.oklab()
+	chroma := math.Hypot(a, b)
+	nearest, nearestDistance := 0, math.Inf(1)
+	for i, entry := range r.paletteOklab {
+		chromaDiff := chroma - math.Hypot(entry[1], entry[2])
+		abDiff := (a-entry[1])*(a-entry[1]) + (b-entry[2])*(b-entry[2])
+		distance := (l-entry[0])*(l-entry[0]) + abDiff + (oklabChromaWeight-1)*chromaDiff*chromaDiff
+		if distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+
+	terminalColor := tcell.PaletteColor(r.palette[nearest])
+
+	// Keep the cache from growing without limit when showing images or many gradients
+	if len(r.cache) >= 1<<16 {
+		clear(r.cache)
+	}
+	r.cache[color] = terminalColor
+
+	return terminalColor
+}
@@ -0,0 +1,380 @@
+package goat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parses a color written the same way as in CSS, which is one of:
+//   - a hex color, such as "#f80", "#ff8800" or "#ff880080"
+//   - a named color, such as "rebeccapurple", or "transparent"
+//   - rgb() or rgba(), such as "rgb(255, 136, 0)" or "rgb(100% 53% 0% / 50%)"
+//   - hsl() or hsla(), such as "hsl(32, 100%, 50%)" or "hsl(32deg 100% 50% / 0.5)"
+//   - oklch(), such as "oklch(0.75 0.18 55)"
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		return parseHexColor(hex)
+	}
+
+	if name, args, ok := strings.Cut(s, "("); ok {
+		args, ok = strings.CutSuffix(args, ")")
+		if !ok {
+			return Color{}, fmt.Errorf("color %q is missing a closing parenthesis", s)
+		}
+
+		values, alpha, err := splitColorArgs(args)
+		if err != nil {
+			return Color{}, fmt.Errorf("color %q: %w", s, err)
+		}
+
+		switch strings.TrimSpace(name) {
+		case "rgb", "rgba":
+			return parseRGBFunc(values, alpha)
+		case "hsl", "hsla":
+			return parseHSLFunc(values, alpha)
+		case "oklch":
+			return parseOKLCHFunc(values, alpha)
+		default:
+			return Color{}, fmt.Errorf("unknown color function %q", name)
+		}
+	}
+
+	if color, ok := cssColorNames[s]; ok {
+		return color, nil
+	}
+
+	return Color{}, fmt.Errorf("unknown color %q", s)
+}
+
+func parseHexColor(hex string) (Color, error) {
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hex color %q", "#"+hex)
+	}
+
+	// Short forms have a single digit for each channel, which is repeated
+	nibble := func(shift int) uint8 {
+		return uint8(value>>shift&0xf) * 0x11
+	}
+	byteAt := func(shift int) uint8 {
+		return uint8(value >> shift)
+	}
+
+	switch len(hex) {
+	case 3:
+		return Color{nibble(8), nibble(4), nibble(0), 0xff}, nil
+	case 4:
+		return Color{nibble(12), nibble(8), nibble(4), nibble(0)}, nil
+	case 6:
+		return Color{byteAt(16), byteAt(8), byteAt(0), 0xff}, nil
+	case 8:
+		return Color{byteAt(24), byteAt(16), byteAt(8), byteAt(0)}, nil
+	default:
+		return Color{}, fmt.Errorf("invalid hex color %q", "#"+hex)
+	}
+}
+
+// Splits the arguments of a color function, which are separated by commas or spaces, with an optional alpha after a slash or as a fourth argument
+func splitColorArgs(args string) ([]string, string, error) {
+	args, alpha, hasSlash := strings.Cut(args, "/")
+
+	values := strings.FieldsFunc(args, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if !hasSlash && len(values) == 4 {
+		alpha = values[3]
+		values = values[:3]
+	}
+	if len(values) != 3 {
+		return nil, "", fmt.Errorf("expected 3 values but got %d", len(values))
+	}
+
+	alpha = strings.TrimSpace(alpha)
+	if hasSlash && alpha == "" {
+		return nil, "", fmt.Errorf("missing alpha after slash")
+	}
+
+	return values, alpha, nil
+}
+
+// Parses a number, or a percentage which is scaled so that 100% is the given value
+func parseColorNumber(s string, percentScale float64) (float64, error) {
+	if number, ok := strings.CutSuffix(s, "%"); ok {
+		value, err := parseFiniteFloat(number)
+		return value / 100 * percentScale, err
+	}
+
+	return parseFiniteFloat(s)
+}
+
+// Parses a number, rejecting NaN and infinities, which strconv.ParseFloat accepts
+func parseFiniteFloat(s string) (float64, error) {
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("number %q is not finite", s)
+	}
+	return value, nil
+}
+
+// Parses a hue in degrees, which may be given in deg, grad, rad or turn units
+func parseHue(s string) (float64, error) {
+	units := []struct {
+		suffix  string
+		degrees float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(s, unit.suffix); ok {
+			value, err := parseFiniteFloat(number)
+			return value * unit.degrees, err
+		}
+	}
+
+	return parseFiniteFloat(s)
+}
+
+// Parses an alpha from 0 to 1, or a percentage, defaulting to opaque when empty
+func parseAlpha(s string) (uint8, error) {
+	if s == "" {
+		return 0xff, nil
+	}
+
+	alpha, err := parseColorNumber(s, 1)
+	if err != nil {
+		return 0, fmt.Errorf("invalid alpha %q", s)
+	}
+
+	return unitToChannel(alpha), nil
+}
+
+// Converts a value from 0 to 1 into a color channel, clamping it to the range of a channel
+func unitToChannel(value float64) uint8 {
+	return uint8(min(max(math.Round(value*0xff), 0), 0xff))
+}
+
+func parseRGBFunc(values []string, alpha string) (Color, error) {
+	channels := [3]uint8{}
+	for i, value := range values {
+		channel, err := parseColorNumber(value, 0xff)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid rgb() value %q", value)
+		}
+		channels[i] = unitToChannel(channel / 0xff)
+	}
+
+	a, err := parseAlpha(alpha)
+	if err != nil {
+		return Color{}, err
+	}
+
+	return Color{channels[0], channels[1], channels[2], a}, nil
+}
+
+func parseHSLFunc(values []string, alpha string) (Color, error) {
+	h, err := parseHue(values[0])
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hsl() hue %q", values[0])
+	}
+	s, err := parseColorNumber(values[1], 1)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hsl() saturation %q", values[1])
+	}
+	l, err := parseColorNumber(values[2], 1)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hsl() lightness %q", values[2])
+	}
+
+	a, err := parseAlpha(alpha)
+	if err != nil {
+		return Color{}, err
+	}
+
+	return ColorHSL(h, s, l).WithAlpha(a), nil
+}
+
+func parseOKLCHFunc(values []string, alpha string) (Color, error) {
+	l, err := parseColorNumber(values[0], 1)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid oklch() lightness %q", values[0])
+	}
+	// 100% chroma is 0.4 in CSS
+	c, err := parseColorNumber(values[1], 0.4)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid oklch() chroma %q", values[1])
+	}
+	h, err := parseHue(values[2])
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid oklch() hue %q", values[2])
+	}
+
+	a, err := parseAlpha(alpha)
+	if err != nil {
+		return Color{}, err
+	}
+
+	return ColorOKLCH(l, c, h).WithAlpha(a), nil
+}
+
+// The named colors of CSS
+var cssColorNames = map[string]Color{
+	"transparent":          {},
+	"aliceblue":            ColorRGB(0xf0, 0xf8, 0xff),
+	"antiquewhite":         ColorRGB(0xfa, 0xeb, 0xd7),
+	"aqua":                 ColorRGB(0x00, 0xff, 0xff),
+	"aquamarine":           ColorRGB(0x7f, 0xff, 0xd4),
+	"azure":                ColorRGB(0xf0, 0xff, 0xff),
+	"beige":                ColorRGB(0xf5, 0xf5, 0xdc),
+	"bisque":               ColorRGB(0xff, 0xe4, 0xc4),
+	"black":                ColorRGB(0x00, 0x00, 0x00),
+	"blanchedalmond":       ColorRGB(0xff, 0xeb, 0xcd),
+	"blue":                 ColorRGB(0x00, 0x00, 0xff),
+	"blueviolet":           ColorRGB(0x8a, 0x2b, 0xe2),
+	"brown":                ColorRGB(0xa5, 0x2a, 0x2a),
+	"burlywood":            ColorRGB(0xde, 0xb8, 0x87),
+	"cadetblue":            ColorRGB(0x5f, 0x9e, 0xa0),
+	"chartreuse":           ColorRGB(0x7f, 0xff, 0x00),
+	"chocolate":            ColorRGB(0xd2, 0x69, 0x1e),
+	"coral":                ColorRGB(0xff, 0x7f, 0x50),
+	"cornflowerblue":       ColorRGB(0x64, 0x95, 0xed),
+	"cornsilk":             ColorRGB(0xff, 0xf8, 0xdc),
+	"crimson":              ColorRGB(0xdc, 0x14, 0x3c),
+	"cyan":                 ColorRGB(0x00, 0xff, 0xff),
+	"darkblue":             ColorRGB(0x00, 0x00, 0x8b),
+	"darkcyan":             ColorRGB(0x00, 0x8b, 0x8b),
+	"darkgoldenrod":        ColorRGB(0xb8, 0x86, 0x0b),
+	"darkgray":             ColorRGB(0xa9, 0xa9, 0xa9),
+	"darkgreen":            ColorRGB(0x00, 0x64, 0x00),
+	"darkgrey":             ColorRGB(0xa9, 0xa9, 0xa9),
+	"darkkhaki":            ColorRGB(0xbd, 0xb7, 0x6b),
+	"darkmagenta":          ColorRGB(0x8b, 0x00, 0x8b),
+	"darkolivegreen":       ColorRGB(0x55, 0x6b, 0x2f),
+	"darkorange":           ColorRGB(0xff, 0x8c, 0x00),
+	"darkorchid":           ColorRGB(0x99, 0x32, 0xcc),
+	"darkred":              ColorRGB(0x8b, 0x00, 0x00),
+	"darksalmon":           ColorRGB(0xe9, 0x96, 0x7a),
+	"darkseagreen":         ColorRGB(0x8f, 0xbc, 0x8f),
+	"darkslateblue":        ColorRGB(0x48, 0x3d, 0x8b),
+	"darkslategray":        ColorRGB(0x2f, 0x4f, 0x4f),
+	"darkslategrey":        ColorRGB(0x2f, 0x4f, 0x4f),
+	"darkturquoise":        ColorRGB(0x00, 0xce, 0xd1),
+	"darkviolet":           ColorRGB(0x94, 0x00, 0xd3),
+	"deeppink":             ColorRGB(0xff, 0x14, 0x93),
+	"deepskyblue":          ColorRGB(0x00, 0xbf, 0xff),
+	"dimgray":              ColorRGB(0x69, 0x69, 0x69),
+	"dimgrey":              ColorRGB(0x69, 0x69, 0x69),
+	"dodgerblue":           ColorRGB(0x1e, 0x90, 0xff),
+	"firebrick":            ColorRGB(0xb2, 0x22, 0x22),
+	"floralwhite":          ColorRGB(0xff, 0xfa, 0xf0),
+	"forestgreen":          ColorRGB(0x22, 0x8b, 0x22),
+	"fuchsia":              ColorRGB(0xff, 0x00, 0xff),
+	"gainsboro":            ColorRGB(0xdc, 0xdc, 0xdc),
+	"ghostwhite":           ColorRGB(0xf8, 0xf8, 0xff),
+	"gold":                 ColorRGB(0xff, 0xd7, 0x00),
+	"goldenrod":            ColorRGB(0xda, 0xa5, 0x20),
+	"gray":                 ColorRGB(0x80, 0x80, 0x80),
+	"green":                ColorRGB(0x00, 0x80, 0x00),
+	"greenyellow":          ColorRGB(0xad, 0xff, 0x2f),
+	"grey":                 ColorRGB(0x80, 0x80, 0x80),
+	"honeydew":             ColorRGB(0xf0, 0xff, 0xf0),
+	"hotpink":              ColorRGB(0xff, 0x69, 0xb4),
+	"indianred":            ColorRGB(0xcd, 0x5c, 0x5c),
+	"indigo":               ColorRGB(0x4b, 0x00, 0x82),
+	"ivory":                ColorRGB(0xff, 0xff, 0xf0),
+	"khaki":                ColorRGB(0xf0, 0xe6, 0x8c),
+	"lavender":             ColorRGB(0xe6, 0xe6, 0xfa),
+	"lavenderblush":        ColorRGB(0xff, 0xf0, 0xf5),
+	"lawngreen":            ColorRGB(0x7c, 0xfc, 0x00),
+	"lemonchiffon":         ColorRGB(0xff, 0xfa, 0xcd),
+	"lightblue":            ColorRGB(0xad, 0xd8, 0xe6),
+	"lightcoral":           ColorRGB(0xf0, 0x80, 0x80),
+	"lightcyan":            ColorRGB(0xe0, 0xff, 0xff),
+	"lightgoldenrodyellow": ColorRGB(0xfa, 0xfa, 0xd2),
+	"lightgray":            ColorRGB(0xd3, 0xd3, 0xd3),
+	"lightgreen":           ColorRGB(0x90, 0xee, 0x90),
+	"lightgrey":            ColorRGB(0xd3, 0xd3, 0xd3),
+	"lightpink":            ColorRGB(0xff, 0xb6, 0xc1),
+	"lightsalmon":          ColorRGB(0xff, 0xa0, 0x7a),
+	"lightseagreen":        ColorRGB(0x20, 0xb2, 0xaa),
+	"lightskyblue":         ColorRGB(0x87, 0xce, 0xfa),
+	"lightslategray":       ColorRGB(0x77, 0x88, 0x99),
+	"lightslategrey":       ColorRGB(0x77, 0x88, 0x99),
+	"lightsteelblue":       ColorRGB(0xb0, 0xc4, 0xde),
+	"lightyellow":          ColorRGB(0xff, 0xff, 0xe0),
+	"lime":                 ColorRGB(0x00, 0xff, 0x00),
+	"limegreen":            ColorRGB(0x32, 0xcd, 0x32),
+	"linen":                ColorRGB(0xfa, 0xf0, 0xe6),
+	"magenta":              ColorRGB(0xff, 0x00, 0xff),
+	"maroon":               ColorRGB(0x80, 0x00, 0x00),
+	"mediumaquamarine":     ColorRGB(0x66, 0xcd, 0xaa),
+	"mediumblue":           ColorRGB(0x00, 0x00, 0xcd),
+	"mediumorchid":         ColorRGB(0xba, 0x55, 0xd3),
+	"mediumpurple":         ColorRGB(0x93, 0x70, 0xdb),
+	"mediumseagreen":       ColorRGB(0x3c, 0xb3, 0x71),
+	"mediumslateblue":      ColorRGB(0x7b, 0x68, 0xee),
+	"mediumspringgreen":    ColorRGB(0x00, 0xfa, 0x9a),
+	"mediumturquoise":      ColorRGB(0x48, 0xd1, 0xcc),
+	"mediumvioletred":      ColorRGB(0xc7, 0x15, 0x85),
+	"midnightblue":         ColorRGB(0x19, 0x19, 0x70),
+	"mintcream":            ColorRGB(0xf5, 0xff, 0xfa),
+	"mistyrose":            ColorRGB(0xff, 0xe4, 0xe1),
+	"moccasin":             ColorRGB(0xff, 0xe4, 0xb5),
+	"navajowhite":          ColorRGB(0xff, 0xde, 0xad),
+	"navy":                 ColorRGB(0x00, 0x00, 0x80),
+	"oldlace":              ColorRGB(0xfd, 0xf5, 0xe6),
+	"olive":                ColorRGB(0x80, 0x80, 0x00),
+	"olivedrab":            ColorRGB(0x6b, 0x8e, 0x23),
+	"orange":               ColorRGB(0xff, 0xa5, 0x00),
+	"orangered":            ColorRGB(0xff, 0x45, 0x00),
+	"orchid":               ColorRGB(0xda, 0x70, 0xd6),
+	"palegoldenrod":        ColorRGB(0xee, 0xe8, 0xaa),
+	"palegreen":            ColorRGB(0x98, 0xfb, 0x98),
+	"paleturquoise":        ColorRGB(0xaf, 0xee, 0xee),
+	"palevioletred":        ColorRGB(0xdb, 0x70, 0x93),
+	"papayawhip":           ColorRGB(0xff, 0xef, 0xd5),
+	"peachpuff":            ColorRGB(0xff, 0xda, 0xb9),
+	"peru":                 ColorRGB(0xcd, 0x85, 0x3f),
+	"pink":                 ColorRGB(0xff, 0xc0, 0xcb),
+	"plum":                 ColorRGB(0xdd, 0xa0, 0xdd),
+	"powderblue":           ColorRGB(0xb0, 0xe0, 0xe6),
+	"purple":               ColorRGB(0x80, 0x00, 0x80),
+	"rebeccapurple":        ColorRGB(0x66, 0x33, 0x99),
+	"red":                  ColorRGB(0xff, 0x00, 0x00),
+	"rosybrown":            ColorRGB(0xbc, 0x8f, 0x8f),
+	"royalblue":            ColorRGB(0x41, 0x69, 0xe1),
+	"saddlebrown":          ColorRGB(0x8b, 0x45, 0x13),
+	"salmon":               ColorRGB(0xfa, 0x80, 0x72),
+	"sandybrown":           ColorRGB(0xf4, 0xa4, 0x60),
+	"seagreen":             ColorRGB(0x2e, 0x8b, 0x57),
+	"seashell":             ColorRGB(0xff, 0xf5, 0xee),
+	"sienna":               ColorRGB(0xa0, 0x52, 0x2d),
+	"silver":               ColorRGB(0xc0, 0xc0, 0xc0),
+	"skyblue":              ColorRGB(0x87, 0xce, 0xeb),
+	"slateblue":            ColorRGB(0x6a, 0x5a, 0xcd),
+	"slategray":            ColorRGB(0x70, 0x80, 0x90),
+	"slategrey":            ColorRGB(0x70, 0x80, 0x90),
+	"snow":                 ColorRGB(0xff, 0xfa, 0xfa),
+	"springgreen":          ColorRGB(0x00, 0xff, 0x7f),
+	"steelblue":            ColorRGB(0x46, 0x82, 0xb4),
+	"tan":                  ColorRGB(0xd2, 0xb4, 0x8c),
+	"teal":                 ColorRGB(0x00, 0x80, 0x80),
+	"thistle":              ColorRGB(0xd8, 0xbf, 0xd8),
+	"tomato":               ColorRGB(0xff, 0x63, 0x47),
+	"turquoise":            ColorRGB(0x40, 0xe0, 0xd0),
+	"violet":               ColorRGB(0xee, 0x82, 0xee),
+	"wheat":                ColorRGB(0xf5, 0xde, 0xb3),
+	"white":                ColorRGB(0xff, 0xff, 0xff),
+	"whitesmoke":           ColorRGB(0xf5, 0xf5, 0xf5),
+	"yellow":               ColorRGB(0xff, 0xff, 0x00),
+	"yellowgreen":          ColorRGB(0x9a, 0xcd, 0x32),
+}
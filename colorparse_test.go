@@ -0,0 +1,71 @@
+package goat
+
+import "testing"
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Color
+		wantErr bool
+	}{
+		{input: "#f80", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "#f808", want: Color{0xff, 0x88, 0x00, 0x88}},
+		{input: "#ff8800", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "#ff880080", want: Color{0xff, 0x88, 0x00, 0x80}},
+		{input: "  #FF8800 ", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "rebeccapurple", want: ColorRGB(0x66, 0x33, 0x99)},
+		{input: "Red", want: ColorRGB(0xff, 0x00, 0x00)},
+		{input: "transparent", want: Color{}},
+		{input: "rgb(255, 136, 0)", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "rgb(255 136 0)", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "rgb(100% 53% 0% / 50%)", want: Color{0xff, 0x87, 0x00, 0x80}},
+		{input: "rgba(255, 0, 0, 0.5)", want: Color{0xff, 0x00, 0x00, 0x80}},
+		{input: "rgb(300, -5, 0)", want: Color{0xff, 0x00, 0x00, 0xff}},
+		{input: "hsl(32, 100%, 50%)", want: Color{0xff, 0x88, 0x00, 0xff}},
+		{input: "hsl(32deg 100% 50% / 0.5)", want: Color{0xff, 0x88, 0x00, 0x80}},
+		{input: "hsla(0.5turn, 100%, 50%, 1)", want: Color{0x00, 0xff, 0xff, 0xff}},
+		{input: "hsl(200grad 100% 50%)", want: Color{0x00, 0xff, 0xff, 0xff}},
+		{input: "oklch(1 0 0)", want: Color{0xff, 0xff, 0xff, 0xff}},
+		{input: "oklch(0% 0 0 / 0)", want: Color{0x00, 0x00, 0x00, 0x00}},
+
+		{input: "", wantErr: true},
+		{input: "notacolor", wantErr: true},
+		{input: "#f8", wantErr: true},
+		{input: "#fffff", wantErr: true},
+		{input: "#ggg", wantErr: true},
+		{input: "rgb(0 0 0", wantErr: true},
+		{input: "rgb(0, 0)", wantErr: true},
+		{input: "rgb(0 0 0 /)", wantErr: true},
+		{input: "rgb(a, 0, 0)", wantErr: true},
+		{input: "cmyk(0 0 0)", wantErr: true},
+		{input: "rgb(NaN, 0, 0)", wantErr: true},
+		{input: "rgb(inf 0 0)", wantErr: true},
+		{input: "rgb(-Infinity 0 0)", wantErr: true},
+		{input: "rgb(1e400 0 0)", wantErr: true},
+		{input: "rgb(0 0 0 / nan)", wantErr: true},
+		{input: "rgb(nan% 0 0)", wantErr: true},
+		{input: "hsl(infdeg, 100%, 50%)", wantErr: true},
+		{input: "hsl(0, nan%, 50%)", wantErr: true},
+		{input: "oklch(0.5 0.1 nan)", wantErr: true},
+		{input: "oklch(inf 0.1 0)", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := ParseColor(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -86,8 +86,8 @@ func (w app) Build() (goat.Widget, error) {
 			goatw.ImageNetwork{
 				Url: cat.Url,
 				LoadingBuilder: func() goat.Widget {
-					return goatw.Text{
-						Text: "Loading cat...",
+					return goatw.Spinner{
+						Label: "Loading cat...",
 					}
 				},
 				ErrorBuilder: func(err error) goat.Widget {
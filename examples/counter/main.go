@@ -18,6 +18,27 @@ var _ goat.StateWidget = app{}
 
 func (w app) Build() (goat.Widget, error) {
 	value, setValue := goat.UseState(0)
+	overlay := goat.UseOverlay()
+
+	confirmReset := func() {
+		confirmed := goatw.ShowDialog(overlay, func(close func(bool)) goat.Widget {
+			return goatw.Dialog{
+				Title: "Reset the counter?",
+				Body:  goatw.Text{Text: "The current count of " + strconv.Itoa(value) + " will be lost."},
+				Actions: []goatw.DialogAction{
+					{Label: "[N] Cancel", KeyActivators: []string{"n"}, OnActivate: func() { close(false) }},
+					{Label: "[Y] Reset", KeyActivators: []string{"y"}, OnActivate: func() { close(true) }},
+				},
+				OnDismiss: func() { close(false) },
+			}
+		})
+
+		go func() {
+			if <-confirmed {
+				setValue(0)
+			}
+		}()
+	}
 
 	buttonPad := goat.EdgeInsertsSymmetric(0, 2)
 
@@ -39,11 +60,9 @@ func (w app) Build() (goat.Widget, error) {
 					MainAxisShrinkWrap: true,
 					Children: []goat.Widget{
 						goatw.Button{
-							Label:   "[R] Reset",
-							Padding: buttonPad,
-							OnActivate: func() {
-								setValue(0)
-							},
+							Label:         "[R] Reset",
+							Padding:       buttonPad,
+							OnActivate:    confirmReset,
 							KeyActivators: []string{"r"},
 						},
 						goatw.SizedBox{Width: 1},
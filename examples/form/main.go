@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	goatw "github.com/jwr1/goat/widget"
+
+	"github.com/jwr1/goat"
+)
+
+type app struct {
+	goat.Widget
+}
+
+var _ goat.StateWidget = app{}
+
+func (w app) Build() (goat.Widget, error) {
+	name, setName := goat.UseState("")
+	password, setPassword := goat.UseState("")
+	submitted, setSubmitted := goat.UseState("")
+
+	submit := func(string) {
+		setSubmitted(fmt.Sprintf("Signed in as %q with a %d character password", name, len([]rune(password))))
+	}
+
+	return goatw.Center{
+		Child: goatw.Column{
+			MainAxisShrinkWrap: true,
+			Children: []goat.Widget{
+				goatw.Text{Text: "Name:"},
+				goatw.TextInput{
+					Value:       name,
+					OnChange:    setName,
+					OnSubmit:    submit,
+					Placeholder: "Press Tab to switch fields",
+					Autofocus:   true,
+				},
+				goatw.SizedBox{Height: 1},
+				goatw.Text{Text: "Password:"},
+				goatw.TextInput{
+					Value:    password,
+					OnChange: setPassword,
+					OnSubmit: submit,
+					Password: true,
+				},
+				goatw.SizedBox{Height: 1},
+				goatw.Text{Text: submitted},
+			},
+		},
+	}, nil
+}
+
+func main() {
+	err := goat.RunApp(app{})
+	if err != nil {
+		panic(err.Error())
+	}
+}
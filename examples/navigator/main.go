@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	goatw "github.com/jwr1/goat/widget"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/jwr1/goat"
+)
+
+type homeScreen struct {
+	goat.Widget
+
+	nav *goatw.NavigatorController
+}
+
+var _ goat.StateWidget = homeScreen{}
+
+func (w homeScreen) Build() (goat.Widget, error) {
+	message, setMessage := goat.UseState("Pick a fruit to count")
+
+	open := func(fruit string) {
+		result := w.nav.PushNamed("fruit", map[string]string{"name": fruit})
+
+		go func() {
+			if count, ok := (<-result).(int); ok {
+				setMessage(fmt.Sprintf("You counted %d %ss", count, fruit))
+			} else {
+				setMessage(fmt.Sprintf("You stopped counting %ss", fruit))
+			}
+		}()
+	}
+
+	buttonPad := goat.EdgeInsertsSymmetric(0, 2)
+
+	return goatw.Center{
+		Child: goatw.Column{
+			MainAxisShrinkWrap: true,
+			CrossAxisAlignment: goatw.CrossAxisAlignmentCenter,
+			Children: []goat.Widget{
+				goatw.Text{Text: message},
+				goatw.SizedBox{Height: 1},
+				goatw.Row{
+					MainAxisShrinkWrap: true,
+					Children: []goat.Widget{
+						goatw.Button{
+							Label:         "[A] Apples",
+							Padding:       buttonPad,
+							OnActivate:    func() { open("apple") },
+							KeyActivators: []string{"a"},
+						},
+						goatw.SizedBox{Width: 1},
+						goatw.Button{
+							Label:         "[P] Pears",
+							Padding:       buttonPad,
+							OnActivate:    func() { open("pear") },
+							KeyActivators: []string{"p"},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+type fruitScreen struct {
+	goat.Widget
+
+	nav   *goatw.NavigatorController
+	fruit string
+}
+
+var _ goat.StateWidget = fruitScreen{}
+
+func (w fruitScreen) Build() (goat.Widget, error) {
+	count, setCount := goat.UseState(0)
+
+	buttonPad := goat.EdgeInsertsSymmetric(0, 2)
+
+	return goatw.Background{
+		Background: goat.Color{R: 0, G: 0, B: 60, A: 0xFF},
+		Child: goatw.Center{
+			Child: goatw.Column{
+				MainAxisShrinkWrap: true,
+				CrossAxisAlignment: goatw.CrossAxisAlignmentCenter,
+				Children: []goat.Widget{
+					goatw.Text{Text: "Counting " + w.fruit + "s: " + strconv.Itoa(count)},
+					goatw.Text{Text: "Press Escape to go back without saving"},
+					goatw.SizedBox{Height: 1},
+					goatw.Row{
+						MainAxisShrinkWrap: true,
+						Children: []goat.Widget{
+							goatw.Button{
+								Label:         "[↑] Count",
+								Padding:       buttonPad,
+								OnActivate:    func() { setCount(count + 1) },
+								KeyActivators: []string{tcell.KeyNames[tcell.KeyUp]},
+							},
+							goatw.SizedBox{Width: 1},
+							goatw.Button{
+								Label:         "[S] Save",
+								Padding:       buttonPad,
+								OnActivate:    func() { w.nav.Pop(count) },
+								KeyActivators: []string{"s"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func main() {
+	err := goat.RunApp(goatw.Navigator{
+		InitialRoute: "home",
+		Transition:   goatw.RouteTransitionSlide,
+		Routes: map[string]goatw.RouteBuilder{
+			"home": func(nav *goatw.NavigatorController, params map[string]string) goat.Widget {
+				return homeScreen{nav: nav}
+			},
+			"fruit": func(nav *goatw.NavigatorController, params map[string]string) goat.Widget {
+				return fruitScreen{nav: nav, fruit: params["name"]}
+			},
+		},
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	goatw "github.com/jwr1/goat/widget"
+
+	"github.com/jwr1/goat"
+)
+
+type theme string
+
+const (
+	themeLight  theme = "light"
+	themeDark   theme = "dark"
+	themeSystem theme = "system"
+)
+
+type app struct {
+	goat.Widget
+}
+
+var _ goat.StateWidget = app{}
+
+func (w app) Build() (goat.Widget, error) {
+	notifications, setNotifications := goat.UseState(goatw.CheckboxChecked)
+	sounds, setSounds := goat.UseState(goatw.CheckboxIndeterminate)
+	asciiOnly, setAsciiOnly := goat.UseState(false)
+	selectedTheme, setSelectedTheme := goat.UseState(themeSystem)
+
+	checkboxGlyphs := goatw.CheckboxGlyphsUnicode
+	switchGlyphs := goatw.SwitchGlyphsUnicode
+	radioGlyphs := goatw.RadioGlyphsUnicode
+	if asciiOnly {
+		checkboxGlyphs = goatw.CheckboxGlyphsASCII
+		switchGlyphs = goatw.SwitchGlyphsASCII
+		radioGlyphs = goatw.RadioGlyphsASCII
+	}
+
+	dimSounds := 0.0
+	onSoundsChange := setSounds
+	if notifications == goatw.CheckboxUnchecked {
+		dimSounds = 0.5
+		onSoundsChange = nil
+	}
+
+	return goatw.Center{
+		Child: goatw.Border{
+			Title: "Settings",
+			Style: goatw.BorderStyleRounded,
+			Child: goatw.Padding{
+				Padding: goat.EdgeInsertsSymmetric(0, 1),
+				Child: goatw.Column{
+					MainAxisShrinkWrap: true,
+					Children: []goat.Widget{
+						goatw.Text{Text: "Press Tab to move, and Space to toggle"},
+						goatw.SizedBox{Height: 1},
+						goatw.Checkbox{
+							Label:     "Show notifications",
+							Value:     notifications,
+							OnChange:  setNotifications,
+							Glyphs:    checkboxGlyphs,
+							Autofocus: true,
+						},
+						// Sounds are only played with notifications, so the option is dimmed and disabled without them
+						goatw.ColorFilter{
+							Dim: dimSounds,
+							Child: goatw.Checkbox{
+								Label:    "Play sounds",
+								Value:    sounds,
+								OnChange: onSoundsChange,
+								Tristate: true,
+								Glyphs:   checkboxGlyphs,
+							},
+						},
+						goatw.Switch{
+							Label:    "Only use ASCII characters",
+							Value:    asciiOnly,
+							OnChange: setAsciiOnly,
+							Glyphs:   switchGlyphs,
+						},
+						goatw.SizedBox{Height: 1},
+						goatw.Text{Text: "Theme:"},
+						goatw.RadioGroup[theme]{
+							Options: []goatw.RadioOption[theme]{
+								{Value: themeLight, Label: "Light"},
+								{Value: themeDark, Label: "Dark"},
+								{Value: themeSystem, Label: "System"},
+							},
+							Value:     selectedTheme,
+							OnChange:  setSelectedTheme,
+							Direction: goatw.AxisHorizontal,
+							Spacing:   2,
+							Glyphs:    radioGlyphs,
+						},
+						goatw.SizedBox{Height: 1},
+						goatw.Text{Text: fmt.Sprintf("Using the %s theme", selectedTheme)},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func main() {
+	err := goat.RunApp(app{})
+	if err != nil {
+		panic(err.Error())
+	}
+}
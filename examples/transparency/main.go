@@ -7,8 +7,6 @@ import (
 	goatw "github.com/jwr1/goat/widget"
 
 	"github.com/jwr1/goat"
-
-	"github.com/gdamore/tcell/v2"
 )
 
 type app struct {
@@ -18,48 +16,19 @@ type app struct {
 var _ goat.StateWidget = app{}
 
 func (w app) Build() (goat.Widget, error) {
-	value, setValue := goat.UseStateFunc(func() uint8 { return 0xff / 2 })
-
-	buttonPad := goat.EdgeInsertsSymmetric(0, 1)
-
-	goat.UseEffect(func() func() {
-		ticker := time.NewTicker(time.Millisecond * 10)
-		done := make(chan bool)
-
-		directionUp := true
-
-		go func() {
-			for {
-				select {
-				case <-done:
-					return
-				case <-ticker.C:
-					setValue(func(value uint8) uint8 {
-						if directionUp {
-							if value == 0xff {
-								directionUp = false
-							}
-						} else {
-							if value == 0x00 {
-								directionUp = true
-							}
-						}
+	// Bounces the opacity between 0 and 0xff, taking 10ms for each step
+	animation := goat.UseAnimation(time.Millisecond*10*0xff, goat.CurveLinear)
+	goat.UseSetup(func() {
+		animation.SetProgress(0.5)
+		animation.Repeat(true)
+	})
 
-						if directionUp {
-							return value + 1
-						} else {
-							return value - 1
-						}
-					})
-				}
-			}
-		}()
+	value := uint8(goat.Tween[int]{Begin: 0, End: 0xff}.Evaluate(animation))
+	setOpacity := func(newValue uint8) {
+		animation.SetProgress(float64(newValue) / 0xff)
+	}
 
-		return func() {
-			ticker.Stop()
-			done <- true
-		}
-	}, []any{})
+	buttonPad := goat.EdgeInsertsSymmetric(0, 1)
 
 	return goatw.Center{
 		Child: goatw.Column{
@@ -69,25 +38,30 @@ func (w app) Build() (goat.Widget, error) {
 				goatw.Text{
 					Text: fmt.Sprint("Opacity: ", value),
 				},
-				goatw.Row{
-					MainAxisShrinkWrap: true,
-					Children: []goat.Widget{
-						goatw.Button{
-							Label:   "[↑] Increment",
-							Padding: buttonPad,
-							OnActivate: func() {
-								setValue(func(value uint8) uint8 { return value + 1 })
-							},
-							KeyActivators: []string{tcell.KeyNames[tcell.KeyUp]},
-						},
-						goatw.Button{
-							Label:   "[↓] Decrement",
-							Padding: buttonPad,
-							OnActivate: func() {
-								setValue(func(value uint8) uint8 { return value - 1 })
-							},
-							KeyActivators: []string{tcell.KeyNames[tcell.KeyDown]},
+				goatw.SizedBox{
+					Width:  32,
+					Height: 1,
+					Child: goatw.Slider{
+						Value: float64(value),
+						Min:   0,
+						Max:   0xff,
+						Step:  1,
+						OnChange: func(newValue float64) {
+							animation.Stop()
+							setOpacity(uint8(newValue))
 						},
+						Autofocus: true,
+					},
+				},
+				goatw.Switch{
+					Label: "Animate",
+					Value: animation.IsAnimating(),
+					OnChange: func(animate bool) {
+						if animate {
+							animation.Repeat(true)
+						} else {
+							animation.Stop()
+						}
 					},
 				},
 				goatw.Row{
@@ -97,7 +71,7 @@ func (w app) Build() (goat.Widget, error) {
 							Label:   "[Z] Set to Zero",
 							Padding: buttonPad,
 							OnActivate: func() {
-								setValue(func(value uint8) uint8 { return 0 })
+								setOpacity(0)
 							},
 							KeyActivators: []string{"z"},
 						},
@@ -105,7 +79,7 @@ func (w app) Build() (goat.Widget, error) {
 							Label:   "[H] Set to Half",
 							Padding: buttonPad,
 							OnActivate: func() {
-								setValue(func(value uint8) uint8 { return 0xff / 2 })
+								setOpacity(0xff / 2)
 							},
 							KeyActivators: []string{"h"},
 						},
@@ -113,7 +87,7 @@ func (w app) Build() (goat.Widget, error) {
 							Label:   "[F] Set to Full",
 							Padding: buttonPad,
 							OnActivate: func() {
-								setValue(func(value uint8) uint8 { return 0xff })
+								setOpacity(0xff)
 							},
 							KeyActivators: []string{"f"},
 						},
@@ -0,0 +1,123 @@
+package goat
+
+import (
+	"sync"
+)
+
+// The element that currently receives keyboard input, or nil if there is none
+var focusedElement *Element
+var focusLock sync.Mutex
+
+func isFocused(e *Element) bool {
+	focusLock.Lock()
+	defer focusLock.Unlock()
+
+	return focusedElement == e
+}
+
+func setFocus(e *Element) {
+	focusLock.Lock()
+	defer focusLock.Unlock()
+
+	if focusedElement == e {
+		return
+	}
+
+	for _, changed := range []*Element{focusedElement, e} {
+		if changed != nil {
+			changed.queueBuild = true
+			changed.queuePaint = true
+		}
+	}
+
+	focusedElement = e
+}
+
+// Releases focus if it is held by the element, which happens when the element is destroyed
+func releaseFocus(e *Element) {
+	focusLock.Lock()
+	defer focusLock.Unlock()
+
+	if focusedElement == e {
+		focusedElement = nil
+	}
+}
+
+// A hook that makes a widget focusable, so it can be reached with Tab and Backtab.
+// Returns whether the widget currently has keyboard focus, along with a function that moves focus to the widget.
+// If autofocus is set, then the widget takes focus when it is first mounted.
+func UseFocus(autofocus bool) (bool, func()) {
+	return UseOptionalFocus(true, autofocus)
+}
+
+// Like UseFocus, but the widget is only focusable while enabled is set, which lets a prop decide whether it can be focused.
+// A widget that stops being focusable also loses focus.
+func UseOptionalFocus(enabled, autofocus bool) (bool, func()) {
+	context := getHookContext()
+	curElement := context.element
+
+	context.focusable = enabled
+	if !enabled {
+		releaseFocus(curElement)
+	}
+
+	UseSetup(func() {
+		if enabled && autofocus {
+			setFocus(curElement)
+		}
+	})
+
+	return isFocused(curElement), func() {
+		setFocus(curElement)
+	}
+}
+
+// Moves focus to the next focusable element inside of the roots, in tree order, wrapping around at the end.
+// Reports whether there were any focusable elements.
+func moveFocus(roots []*Element, backwards bool) bool {
+	var focusable []*Element
+	for _, root := range roots {
+		collectFocusable(root, &focusable)
+	}
+
+	if len(focusable) == 0 {
+		return false
+	}
+
+	focusLock.Lock()
+	current := -1
+	for i, e := range focusable {
+		if e == focusedElement {
+			current = i
+		}
+	}
+	focusLock.Unlock()
+
+	next := 0
+	switch {
+	case current == -1 && backwards:
+		next = len(focusable) - 1
+	case current == -1:
+		next = 0
+	case backwards:
+		next = (current - 1 + len(focusable)) % len(focusable)
+	default:
+		next = (current + 1) % len(focusable)
+	}
+
+	setFocus(focusable[next])
+
+	return true
+}
+
+func collectFocusable(thisElement *Element, result *[]*Element) {
+	if thisElement.focusable {
+		*result = append(*result, thisElement)
+	}
+
+	for _, key := range sortedChildKeys(thisElement) {
+		if !thisElement.children[key].offstage {
+			collectFocusable(thisElement.children[key], result)
+		}
+	}
+}
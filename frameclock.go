@@ -0,0 +1,87 @@
+package goat
+
+import (
+	"sync"
+	"time"
+)
+
+// A widget waiting for frames from the frame clock
+type frameSubscriber struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// Drives every animation in the app, so that widgets rebuild on a shared schedule instead of each running their own ticker.
+// It is advanced by the render loop, so no goroutines are involved.
+var frameClock = struct {
+	lock        sync.Mutex
+	subscribers map[*Element]*frameSubscriber
+	// Number of times the tree has been rendered, which lets the clock tell which elements were rendered in the last frame
+	renderCount uint64
+}{
+	subscribers: make(map[*Element]*frameSubscriber),
+}
+
+// A hook that rebuilds the widget every interval for as long as it is mounted, returning the time of the current build.
+// Animations should be calculated from the returned time, since frames are skipped while the widget is offstage or outside of the screen.
+// An interval of zero stops the widget from being rebuilt.
+func UseFrameClock(interval time.Duration) time.Time {
+	context := getHookContext()
+	curElement := context.element
+
+	UseEffect(func() func() {
+		if interval <= 0 {
+			return nil
+		}
+
+		frameClock.lock.Lock()
+		frameClock.subscribers[curElement] = &frameSubscriber{
+			interval: interval,
+			next:     time.Now().Add(interval),
+		}
+		frameClock.lock.Unlock()
+
+		return func() {
+			frameClock.lock.Lock()
+			delete(frameClock.subscribers, curElement)
+			frameClock.lock.Unlock()
+		}
+	}, []any{interval})
+
+	return time.Now()
+}
+
+// Queues a build of every subscriber whose next frame is due, skipping those that were not visible on the screen in the last render.
+// Must be called before each render, with the tree locked.
+func advanceFrameClock(now time.Time, screenSize Size) {
+	frameClock.lock.Lock()
+	defer frameClock.lock.Unlock()
+
+	screen := RectXYWH(0, 0, screenSize.Width.Int(), screenSize.Height.Int())
+
+	for element, subscriber := range frameClock.subscribers {
+		if now.Before(subscriber.next) {
+			continue
+		}
+
+		// Keep to the schedule, unless frames were missed, such as while the widget was hidden
+		subscriber.next = subscriber.next.Add(subscriber.interval)
+		if subscriber.next.Before(now) {
+			subscriber.next = now.Add(subscriber.interval)
+		}
+
+		rect := RectXYWH(element.renderAbsPos.X, element.renderAbsPos.Y, element.size.Width.Int(), element.size.Height.Int())
+		visible := element.renderCount == frameClock.renderCount && !rect.Intersect(screen).IsEmpty()
+		if visible {
+			element.queueBuild = true
+			element.queuePaint = true
+		}
+	}
+
+	frameClock.renderCount++
+}
+
+// Records that the element is being rendered in the current frame
+func markRendered(element *Element) {
+	element.renderCount = frameClock.renderCount
+}
@@ -4,6 +4,7 @@ go 1.23.2
 
 require (
 	github.com/gdamore/tcell/v2 v2.7.4
+	github.com/rivo/uniseg v0.4.3
 	golang.org/x/image v0.21.0
 )
 
@@ -11,7 +12,6 @@ require (
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
-	github.com/rivo/uniseg v0.4.3 // indirect
 	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
@@ -9,6 +9,7 @@ type hookContext struct {
 	refIndex   int
 	effects    []effect
 	eventFuncs []func(context EventContext)
+	focusable  bool
 }
 
 var currentHookContext hookContext
@@ -21,6 +22,7 @@ func setupHooks(e *Element) {
 
 func resetHooks() {
 	globalHookEventListeners[currentHookContext.element] = currentHookContext.eventFuncs
+	currentHookContext.element.focusable = currentHookContext.focusable
 	currentHookContext = hookContext{}
 }
 
@@ -43,6 +45,12 @@ func UseTriggerRender() func() {
 	}
 }
 
+// A hook that returns the element of the widget, which stays the same for as long as the widget is mounted.
+// This is mostly useful for referring to where the widget is rendered, such as when anchoring an OverlayEntry.
+func UseElement() *Element {
+	return getHookContext().element
+}
+
 // The most primitive hook to associate persistent data with a widget. A getter function is passed in that will be run once to retrieve the initial value and a reference to the variable is returned by the hook.
 //
 // If you need to pass in the initial value directly, use UseRef instead. If the value you're using is needed for rendering (which is the case the majority of the time), then use one of the UseState hooks.
@@ -65,6 +73,13 @@ type EventContext struct {
 	Event      tcell.Event
 	RenderPos  Pos
 	RenderSize Size
+	// Reports whether the widget that registered the listener has keyboard focus
+	Focused bool
+}
+
+// Reports whether the screen position is inside of the area the widget was rendered to
+func (c EventContext) Contains(x, y int) bool {
+	return RectXYWH(c.RenderPos.X, c.RenderPos.Y, c.RenderSize.Width.Int(), c.RenderSize.Height.Int()).Contains(Pos{X: x, Y: y})
 }
 
 func UseEvent(fn func(context EventContext)) {
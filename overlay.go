@@ -0,0 +1,239 @@
+package goat
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A widget displayed above the rest of the app, which is neither laid out nor clipped by the widget that inserted it
+type OverlayEntry struct {
+	element *Element
+	widget  Widget
+	owner   *Element
+
+	pos     Pos
+	anchor  *Element
+	barrier bool
+	removed bool
+
+	// The element that had focus before a barrier entry was inserted, which regains focus when the entry is removed
+	previousFocus *Element
+}
+
+// Changes where an OverlayEntry is placed, or how it behaves
+type OverlayOption func(entry *OverlayEntry)
+
+// Places the entry at a position relative to the top left corner of the screen
+func OverlayAt(pos Pos) OverlayOption {
+	return func(entry *OverlayEntry) {
+		entry.pos = pos
+		entry.anchor = nil
+	}
+}
+
+// Places the entry at an offset from wherever the anchor element is rendered, following it as it moves
+func OverlayAnchor(anchor *Element, offset Pos) OverlayOption {
+	return func(entry *OverlayEntry) {
+		entry.pos = offset
+		entry.anchor = anchor
+	}
+}
+
+// Stops widgets below the entry, including other entries, from receiving events or focus while the entry is shown
+func OverlayBarrier() OverlayOption {
+	return func(entry *OverlayEntry) {
+		entry.barrier = true
+	}
+}
+
+// Entries in the order they are painted, with the last being on top
+var overlayEntries []*OverlayEntry
+var overlayLock sync.Mutex
+
+// Replaces the widget displayed by the entry
+func (entry *OverlayEntry) Update(widget Widget, options ...OverlayOption) {
+	overlayLock.Lock()
+	defer overlayLock.Unlock()
+
+	entry.widget = widget
+	for _, option := range options {
+		option(entry)
+	}
+}
+
+// Removes the entry from the overlay, destroying its widgets
+func (entry *OverlayEntry) Remove() {
+	overlayLock.Lock()
+	defer overlayLock.Unlock()
+
+	entry.removed = true
+}
+
+func (entry *OverlayEntry) absolutePos() Pos {
+	if entry.anchor != nil {
+		return entry.anchor.renderAbsPos.Add(entry.pos)
+	}
+	return entry.pos
+}
+
+// Inserts widgets into the app's overlay on behalf of the widget that used UseOverlay
+type Overlay struct {
+	owner *Element
+}
+
+// A hook for displaying widgets above the rest of the app, such as popups and dropdowns.
+// Entries should be inserted from event listeners or effects, not directly in Build(), and are removed when the widget is destroyed.
+func UseOverlay() Overlay {
+	context := getHookContext()
+	curElement := context.element
+
+	UseCleanup(func() {
+		overlayLock.Lock()
+		defer overlayLock.Unlock()
+
+		for _, entry := range overlayEntries {
+			if entry.owner == curElement {
+				entry.removed = true
+			}
+		}
+	})
+
+	return Overlay{owner: curElement}
+}
+
+// Displays the widget above everything currently in the overlay, which is placed at the top left of the screen unless an option says otherwise
+// Mouse events over the entry are only delivered to the entry, and not to the widgets below it.
+func (o Overlay) Insert(widget Widget, options ...OverlayOption) *OverlayEntry {
+	entry := &OverlayEntry{
+		element: &Element{},
+		widget:  widget,
+		owner:   o.owner,
+	}
+	for _, option := range options {
+		option(entry)
+	}
+
+	if entry.barrier {
+		focusLock.Lock()
+		entry.previousFocus = focusedElement
+		focusLock.Unlock()
+	}
+
+	overlayLock.Lock()
+	defer overlayLock.Unlock()
+
+	overlayEntries = append(overlayEntries, entry)
+
+	return entry
+}
+
+// Destroys removed entries, then builds and lays out the rest, with each being allowed to take up the whole screen
+func rebuildOverlays(screenSize Size) error {
+	var removed []*OverlayEntry
+	var entries []*OverlayEntry
+	var widgets []Widget
+
+	overlayLock.Lock()
+	for _, entry := range overlayEntries {
+		if entry.removed {
+			removed = append(removed, entry)
+		} else {
+			entries = append(entries, entry)
+			widgets = append(widgets, entry.widget)
+		}
+	}
+	overlayEntries = slices.Clone(entries)
+	overlayLock.Unlock()
+
+	for _, entry := range removed {
+		destroyTree(entry.element)
+
+		if entry.previousFocus != nil && entry.previousFocus.isInitialized {
+			setFocus(entry.previousFocus)
+		}
+	}
+
+	for i, entry := range entries {
+		err := rebuildTree(widgets[i], entry.element, screenSize.LooseConstraints())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Paints every entry onto the canvas, in order
+func renderOverlays(canvas *Canvas) error {
+	overlayLock.Lock()
+	entries := slices.Clone(overlayEntries)
+	positions := make([]Pos, len(entries))
+	for i, entry := range entries {
+		positions[i] = entry.absolutePos()
+	}
+	overlayLock.Unlock()
+
+	for i, entry := range entries {
+		// Entries inserted since the last layout have not been built yet
+		if !entry.element.isInitialized {
+			continue
+		}
+
+		pos := positions[i]
+		entry.element.renderAbsPos = pos
+
+		entryCanvas, err := renderTree(entry.element)
+		if err != nil {
+			return err
+		}
+
+		canvas.OverlayCanvas(pos.X, pos.Y, entryCanvas)
+	}
+
+	return nil
+}
+
+func destroyOverlays() {
+	overlayLock.Lock()
+	entries := overlayEntries
+	overlayEntries = nil
+	overlayLock.Unlock()
+
+	for _, entry := range entries {
+		destroyTree(entry.element)
+	}
+}
+
+// Returns the roots of the element trees that receive the event, or that can take focus when event is nil.
+// The topmost overlay entries come first, and the app's root comes last unless an entry has a barrier.
+// A mouse event over an entry is taken by that entry, so it does not reach anything below it.
+func eventRoots(root *Element, event tcell.Event) []*Element {
+	overlayLock.Lock()
+	defer overlayLock.Unlock()
+
+	mouseEvent, isMouse := event.(*tcell.EventMouse)
+
+	var roots []*Element
+	for i := len(overlayEntries) - 1; i >= 0; i-- {
+		entry := overlayEntries[i]
+		if entry.removed || !entry.element.isInitialized {
+			continue
+		}
+
+		roots = append(roots, entry.element)
+		if entry.barrier {
+			return roots
+		}
+		if isMouse {
+			x, y := mouseEvent.Position()
+			element := entry.element
+			if RectXYWH(element.renderAbsPos.X, element.renderAbsPos.Y, element.size.Width.Int(), element.size.Height.Int()).Contains(Pos{X: x, Y: y}) {
+				return roots
+			}
+		}
+	}
+
+	return append(roots, root)
+}
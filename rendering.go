@@ -4,6 +4,8 @@ type LayoutContext struct {
 	Constraints   Constraints
 	LayoutChild   func(key int, c Widget, constraints Constraints) (Size, error)
 	PositionChild func(key int, pos Pos) error
+	// Keeps a child and its state alive without painting it or letting it receive events or focus, until the next layout
+	SetChildOffstage func(key int, offstage bool) error
 	// PositionChildViewport func(key int, pos Pos, childStart Pos, childEnd Pos) error
 }
 
@@ -27,6 +29,33 @@ type StateWidget interface {
 	Build() (Widget, error)
 }
 
+// Implemented by render widgets that alter the canvas composited from themselves and all their descendants, such as to change its opacity
+type CompositeWidget interface {
+	RenderWidget
+	Composite(canvas *Canvas) error
+}
+
+// Implemented by render widgets whose children join box drawing lines where they are drawn over each other, instead of the top line replacing the one below
+type LineJoiningWidget interface {
+	RenderWidget
+	JoinsLines() bool
+}
+
+// Implemented by widgets that pass information about themselves to the render widget laying them out, such as how much of a Flex they should fill.
+// The parent reads it from the widget it is given before laying it out, and it is kept on the element as its RenderParentData afterwards.
+type ParentDataWidget interface {
+	Widget
+	ParentData() any
+}
+
+// Returns the parent data of the widget, or nil if it is not a ParentDataWidget
+func ParentDataOf(w Widget) any {
+	if w, ok := w.(ParentDataWidget); ok {
+		return w.ParentData()
+	}
+	return nil
+}
+
 type effect struct {
 	setup        func() func()
 	cleanup      func()
@@ -40,10 +69,13 @@ type Element struct {
 	pos             Pos
 	renderCanvas    Canvas
 	renderAbsPos    Pos
+	renderCount     uint64
 	prevConstraints Constraints
 
 	queueBuild bool
 	queuePaint bool
+	focusable  bool
+	offstage   bool
 
 	parent   *Element
 	children map[int]*Element
@@ -64,7 +96,7 @@ func (e *Element) SetRenderData(renderData any) {
 }
 
 func (e *Element) RenderParentData() any {
-	return e.renderData
+	return e.renderParentData
 }
 
 func (e *Element) SetRenderParentData(renderParentData any) {
@@ -79,6 +111,16 @@ func (e *Element) MarkNeedsPaint() {
 	e.queuePaint = true
 }
 
+// Returns the size the element was laid out with
+func (e *Element) Size() Size {
+	return e.size
+}
+
+// Returns the position of the element's top left corner on the screen, as of the last render
+func (e *Element) AbsolutePos() Pos {
+	return e.renderAbsPos
+}
+
 func (e *Element) Parent() *Element {
 	return e.parent
 }
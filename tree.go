@@ -108,17 +108,23 @@ build:
 		layoutContext := LayoutContext{
 			Constraints: constraints,
 			LayoutChild: func(key int, c Widget, constraints Constraints) (Size, error) {
-				childElement, ok := oldChildren[key]
+				// A child can be laid out more than once, such as to measure it before deciding its final constraints
+				childElement, ok := newChildren[key]
 				if !ok {
-					childElement = &Element{parent: thisElement}
-				} else {
-					delete(oldChildren, key)
+					childElement, ok = oldChildren[key]
+					if !ok {
+						childElement = &Element{parent: thisElement}
+					} else {
+						delete(oldChildren, key)
+					}
 				}
 				err := rebuildTree(c, childElement, constraints)
 				if err != nil {
 					return Size{}, err
 				}
 
+				childElement.offstage = false
+				childElement.renderParentData = ParentDataOf(c)
 				newChildren[key] = childElement
 				return childElement.size, nil
 			},
@@ -130,6 +136,16 @@ build:
 
 				childElement.pos = pos
 
+				return nil
+			},
+			SetChildOffstage: func(key int, offstage bool) error {
+				childElement, ok := newChildren[key]
+				if !ok {
+					return fmt.Errorf("LayoutChild() must be called before SetChildOffstage()")
+				}
+
+				childElement.offstage = offstage
+
 				return nil
 			},
 		}
@@ -170,14 +186,18 @@ build:
 func renderTree(thisElement *Element) (Canvas, error) {
 	var resultCanvas Canvas
 
+	markRendered(thisElement)
+
 	switch widget := thisElement.widget.(type) {
 	case StateWidget:
 		childElement := thisElement.children[0]
+		childElement.renderAbsPos = thisElement.renderAbsPos
 		canvas, err := renderTree(childElement)
 		if err != nil {
 			return Canvas{}, err
 		}
-		resultCanvas = canvas
+		// The child is the whole of what a StateWidget draws, so it is returned as is instead of being overlaid again below
+		return canvas, nil
 
 	case RenderWidget:
 		if thisElement.queuePaint {
@@ -202,13 +222,30 @@ func renderTree(thisElement *Element) (Canvas, error) {
 		panic("widget not implemented")
 	}
 
-	for _, childElement := range thisElement.children {
+	// Children are painted in order of their keys, so later children appear on top of earlier ones
+	for _, key := range sortedChildKeys(thisElement) {
+		childElement := thisElement.children[key]
+		if childElement.offstage {
+			continue
+		}
+
 		childElement.renderAbsPos = thisElement.renderAbsPos.Add(childElement.pos)
 		childCanvas, err := renderTree(childElement)
 		if err != nil {
 			return Canvas{}, err
 		}
-		resultCanvas.OverlayCanvas(childElement.pos.X, childElement.pos.Y, childCanvas)
+		if joiner, ok := thisElement.widget.(LineJoiningWidget); ok && joiner.JoinsLines() {
+			resultCanvas.OverlayCanvasJoiningLines(childElement.pos.X, childElement.pos.Y, childCanvas)
+		} else {
+			resultCanvas.OverlayCanvas(childElement.pos.X, childElement.pos.Y, childCanvas)
+		}
+	}
+
+	if widget, ok := thisElement.widget.(CompositeWidget); ok {
+		err := widget.Composite(&resultCanvas)
+		if err != nil {
+			return Canvas{}, err
+		}
 	}
 
 	return resultCanvas, nil
@@ -225,6 +262,9 @@ func destroyTree(thisElement *Element) {
 		}
 	}
 
+	delete(globalHookEventListeners, thisElement)
+	releaseFocus(thisElement)
+
 	*thisElement = Element{}
 }
 
@@ -269,6 +309,16 @@ func stringifyTree(thisElement *Element, builder *strings.Builder, indent int) {
 		builder.WriteString(fmt.Sprintf("%s %s: %v\n", fieldName, fieldType, fieldValue))
 	}
 
+	for _, key := range sortedChildKeys(thisElement) {
+		builder.WriteString(strIndent)
+		builder.WriteString("child ")
+		builder.WriteString(strconv.Itoa(key))
+		builder.WriteString(":\n")
+		stringifyTree(thisElement.children[key], builder, indent+2)
+	}
+}
+
+func sortedChildKeys(thisElement *Element) []int {
 	childKeys := make([]int, 0, len(thisElement.children))
 	for k := range thisElement.children {
 		childKeys = append(childKeys, k)
@@ -276,11 +326,5 @@ func stringifyTree(thisElement *Element, builder *strings.Builder, indent int) {
 
 	sort.Ints(childKeys)
 
-	for _, key := range childKeys {
-		builder.WriteString(strIndent)
-		builder.WriteString("child ")
-		builder.WriteString(strconv.Itoa(key))
-		builder.WriteString(":\n")
-		stringifyTree(thisElement.children[key], builder, indent+2)
-	}
+	return childKeys
 }
@@ -33,6 +33,64 @@ func (pos Pos) Sub(other Pos) Pos {
 	}
 }
 
+// A rectangle of cells, where Min is inclusive and Max is exclusive
+type Rect struct {
+	Min Pos
+	Max Pos
+}
+
+// Creates a new Rect from a position and a size
+func RectXYWH(x, y, width, height int) Rect {
+	return Rect{
+		Min: Pos{X: x, Y: y},
+		Max: Pos{X: x + width, Y: y + height},
+	}
+}
+
+func (r Rect) String() string {
+	return fmt.Sprintf("[%s,%s)", r.Min.String(), r.Max.String())
+}
+
+func (r Rect) Width() int {
+	return r.Max.X - r.Min.X
+}
+
+func (r Rect) Height() int {
+	return r.Max.Y - r.Min.Y
+}
+
+// Reports whether the Rect contains no cells
+func (r Rect) IsEmpty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Reports whether the position is inside of the Rect
+func (r Rect) Contains(p Pos) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X &&
+		r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+// Returns the largest Rect contained by both Rects, which may be empty
+func (r Rect) Intersect(other Rect) Rect {
+	result := Rect{
+		Min: Pos{X: max(r.Min.X, other.Min.X), Y: max(r.Min.Y, other.Min.Y)},
+		Max: Pos{X: min(r.Max.X, other.Max.X), Y: min(r.Max.Y, other.Max.Y)},
+	}
+	if result.IsEmpty() {
+		return Rect{}
+	}
+
+	return result
+}
+
+// Returns the Rect moved by the given offset
+func (r Rect) Add(offset Pos) Rect {
+	return Rect{
+		Min: r.Min.Add(offset),
+		Max: r.Max.Add(offset),
+	}
+}
+
 type RenderViewport struct {
 	AbsoluteStart Pos
 	AbsoluteEnd   Pos
@@ -220,6 +278,28 @@ func (c Constraints) Check(size Size) bool {
 		c.Min.Height.Int() <= size.Height.Int() && size.Height.Int() <= c.Max.Height.Int()
 }
 
+// Returns constraints with the same maximum, and no minimum
+func (c Constraints) Loosen() Constraints {
+	return Constraints{Max: c.Max}
+}
+
+// Returns constraints that are as close to these as possible, while still being within the parent constraints.
+// A zero maximum width or height is treated as unbounded, so that constraints which only set a minimum keep the parent's maximum.
+func (c Constraints) Enforce(parent Constraints) Constraints {
+	maxSize := c.Max
+	if maxSize.Width.IsZero() {
+		maxSize.Width = DimensionInfinite
+	}
+	if maxSize.Height.IsZero() {
+		maxSize.Height = DimensionInfinite
+	}
+
+	return Constraints{
+		Min: c.Min.Clamp(parent),
+		Max: maxSize.Clamp(parent),
+	}
+}
+
 type ConstraintViolationErr struct {
 	constraints Constraints
 	realSize    Size
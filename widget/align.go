@@ -0,0 +1,76 @@
+package goatw
+
+import (
+	. "github.com/jwr1/goat"
+)
+
+// A point within a rectangle, as a fraction of its width and height.
+// The zero value is the top left corner, (0.5, 0.5) is the center, and (1, 1) is the bottom right corner.
+type Alignment struct {
+	X float64
+	Y float64
+}
+
+var (
+	AlignmentTopLeft      = Alignment{0, 0}
+	AlignmentTopCenter    = Alignment{0.5, 0}
+	AlignmentTopRight     = Alignment{1, 0}
+	AlignmentCenterLeft   = Alignment{0, 0.5}
+	AlignmentCenter       = Alignment{0.5, 0.5}
+	AlignmentCenterRight  = Alignment{1, 0.5}
+	AlignmentBottomLeft   = Alignment{0, 1}
+	AlignmentBottomCenter = Alignment{0.5, 1}
+	AlignmentBottomRight  = Alignment{1, 1}
+)
+
+// Returns the position of a child inside of a parent, such that the alignment point of both lines up
+func (a Alignment) Offset(parent Size, child Size) Pos {
+	remaining := parent.Sub(child)
+
+	return Pos{
+		X: int(float64(remaining.Width.Int()) * a.X),
+		Y: int(float64(remaining.Height.Int()) * a.Y),
+	}
+}
+
+// Places its child within itself according to the alignment, giving the child loose constraints.
+// It takes up as much space as it can, unless a size factor is set, or the space is unbounded.
+type Align struct {
+	Widget
+
+	Child     Widget
+	Alignment Alignment
+	// If at least 1, the width is the child's width multiplied by this factor
+	WidthFactor float64
+	// If at least 1, the height is the child's height multiplied by this factor
+	HeightFactor float64
+}
+
+var _ RenderWidget = Align{}
+
+func (w Align) Layout(context LayoutContext) (Size, error) {
+	childSize, err := context.LayoutChild(0, w.Child, context.Constraints.Loosen())
+	if err != nil {
+		return Size{}, err
+	}
+
+	size := context.Constraints.Max
+	if w.WidthFactor >= 1 || size.Width.IsInf() {
+		size.Width = DimensionInt(int(float64(childSize.Width.Int()) * max(w.WidthFactor, 1)))
+	}
+	if w.HeightFactor >= 1 || size.Height.IsInf() {
+		size.Height = DimensionInt(int(float64(childSize.Height.Int()) * max(w.HeightFactor, 1)))
+	}
+	size = size.Clamp(context.Constraints)
+
+	err = context.PositionChild(0, w.Alignment.Offset(size, childSize))
+	if err != nil {
+		return Size{}, err
+	}
+
+	return size, nil
+}
+
+func (w Align) Paint(context PaintContext) error {
+	return nil
+}
@@ -0,0 +1,279 @@
+package goatw
+
+import (
+	"reflect"
+	"time"
+
+	. "github.com/jwr1/goat"
+)
+
+// Length of implicit animations when their Duration is zero
+const defaultImplicitAnimationDuration = 200 * time.Millisecond
+
+// A hook that returns a value which animates towards the target whenever the target changes, starting from wherever it was at the time.
+// The first target is returned as is, without animating, and the animation starts in an effect after the build where the target changes.
+func useImplicitAnimation[T comparable](target T, duration time.Duration, curve Curve, lerp func(begin, end T, t float64) T) T {
+	if duration == 0 {
+		duration = defaultImplicitAnimationDuration
+	}
+
+	animation := UseAnimation(duration, curve)
+	tween := UseRef(&struct{ begin, end T }{target, target})
+
+	UseEffect(func() func() {
+		if target != tween.end {
+			tween.begin = lerp(tween.begin, tween.end, animation.Value())
+			tween.end = target
+			animation.SetProgress(0)
+			animation.Forward()
+		}
+		return nil
+	}, []any{target})
+
+	return lerp(tween.begin, tween.end, animation.Value())
+}
+
+func lerpInt(begin, end int, t float64) int {
+	return Tween[int]{Begin: begin, End: end}.Lerp(t)
+}
+
+func lerpFloat(begin, end float64, t float64) float64 {
+	return Tween[float64]{Begin: begin, End: end}.Lerp(t)
+}
+
+// Interpolates between colors, treating a fully transparent color as the other color with no alpha, so that fading in and out does not pass through black
+func lerpColor(begin, end Color, t float64) Color {
+	if begin.A == 0 {
+		begin = Color{R: end.R, G: end.G, B: end.B}
+	}
+	if end.A == 0 {
+		end = Color{R: begin.R, G: begin.G, B: begin.B}
+	}
+	return Tween[Color]{Begin: begin, End: end}.Lerp(t)
+}
+
+// A Background that animates to its new color when it changes
+type AnimatedBackground struct {
+	Widget
+
+	Child      Widget
+	Background Color
+	// Length of the animation, defaulting to 200ms when zero
+	Duration time.Duration
+	// Defaults to CurveLinear when nil
+	Curve Curve
+}
+
+var _ StateWidget = AnimatedBackground{}
+
+func (w AnimatedBackground) Build() (Widget, error) {
+	return Background{
+		Child:      w.Child,
+		Background: useImplicitAnimation(w.Background, w.Duration, w.Curve, lerpColor),
+	}, nil
+}
+
+// Fades its child to the given opacity, from 0 for invisible to 1 for fully visible, animating when it changes
+type AnimatedOpacity struct {
+	Widget
+
+	Child   Widget
+	Opacity float64
+	// Length of the animation, defaulting to 200ms when zero
+	Duration time.Duration
+	// Defaults to CurveLinear when nil
+	Curve Curve
+}
+
+var _ StateWidget = AnimatedOpacity{}
+
+func (w AnimatedOpacity) Build() (Widget, error) {
+	return Opacity{
+		Child: w.Child,
+		Value: useImplicitAnimation(min(max(w.Opacity, 0), 1), w.Duration, w.Curve, lerpFloat),
+	}, nil
+}
+
+// A Padding that animates to its new padding when it changes
+type AnimatedPadding struct {
+	Widget
+
+	Child   Widget
+	Padding EdgeInserts
+	// Length of the animation, defaulting to 200ms when zero
+	Duration time.Duration
+	// Defaults to CurveLinear when nil
+	Curve Curve
+}
+
+var _ StateWidget = AnimatedPadding{}
+
+func (w AnimatedPadding) Build() (Widget, error) {
+	padding := useImplicitAnimation(w.Padding, w.Duration, w.Curve, func(begin, end EdgeInserts, t float64) EdgeInserts {
+		return EdgeInserts{
+			Top:    lerpInt(begin.Top, end.Top, t),
+			Left:   lerpInt(begin.Left, end.Left, t),
+			Right:  lerpInt(begin.Right, end.Right, t),
+			Bottom: lerpInt(begin.Bottom, end.Bottom, t),
+		}
+	})
+
+	return Padding{
+		Child:   w.Child,
+		Padding: padding,
+	}, nil
+}
+
+// An Align that moves its child to the new alignment when it changes
+type AnimatedAlign struct {
+	Widget
+
+	Child     Widget
+	Alignment Alignment
+	// Length of the animation, defaulting to 200ms when zero
+	Duration time.Duration
+	// Defaults to CurveLinear when nil
+	Curve Curve
+}
+
+var _ StateWidget = AnimatedAlign{}
+
+func (w AnimatedAlign) Build() (Widget, error) {
+	alignment := useImplicitAnimation(w.Alignment, w.Duration, w.Curve, func(begin, end Alignment, t float64) Alignment {
+		return Alignment{
+			X: lerpFloat(begin.X, end.X, t),
+			Y: lerpFloat(begin.Y, end.Y, t),
+		}
+	})
+
+	return Align{
+		Child:     w.Child,
+		Alignment: alignment,
+	}, nil
+}
+
+// Cross-fades from its old child to its new one whenever ChildKey changes.
+// The old child is shown as it was last built while it fades out, and the new child keeps its state for as long as ChildKey stays the same.
+type AnimatedSwitcher struct {
+	Widget
+
+	Child Widget
+	// Identifies the child, where a change is found with reflect.DeepEqual, so any value can be used
+	ChildKey any
+	// Length of the cross-fade, defaulting to 200ms when zero
+	Duration time.Duration
+	// Defaults to CurveLinear when nil
+	Curve Curve
+}
+
+var _ StateWidget = AnimatedSwitcher{}
+
+type animatedSwitcherState struct {
+	key    any
+	nextID int
+	// Id of the current child, and of the child fading out, if any
+	currentID  int
+	outgoingID int
+	outgoing   Widget
+	// Set when the child has changed, until the cross-fade is started by an effect after the build
+	starting bool
+}
+
+func (w AnimatedSwitcher) Build() (Widget, error) {
+	duration := w.Duration
+	if duration == 0 {
+		duration = defaultImplicitAnimationDuration
+	}
+
+	animation := UseAnimation(duration, w.Curve)
+	state := UseRefFunc(func() *animatedSwitcherState {
+		return &animatedSwitcherState{key: w.ChildKey, nextID: 1}
+	})
+	lastChild := UseRef(&w.Child)
+
+	if !reflect.DeepEqual(w.ChildKey, state.key) {
+		// Only the latest old child fades out, so switching again during a cross-fade drops the oldest one
+		state.key = w.ChildKey
+		state.outgoingID = state.currentID
+		state.outgoing = *lastChild
+		state.currentID = state.nextID
+		state.nextID++
+		state.starting = true
+	}
+	*lastChild = w.Child
+
+	UseEffect(func() func() {
+		if state.starting {
+			state.starting = false
+			animation.SetProgress(0)
+			animation.Forward()
+		}
+		return nil
+	}, []any{state.currentID})
+
+	// The cross-fade is yet to start in the build where the child changes, so it is shown at its beginning
+	progress := animation.Value()
+	if state.starting {
+		progress = 0
+	} else if !animation.IsAnimating() {
+		state.outgoing = nil
+	}
+
+	entries := []switcherEntry{}
+	if state.outgoing != nil {
+		entries = append(entries, switcherEntry{id: state.outgoingID, child: state.outgoing, opacity: 1 - progress})
+	}
+	opacity := 1.0
+	if state.outgoing != nil {
+		opacity = progress
+	}
+	entries = append(entries, switcherEntry{id: state.currentID, child: w.Child, opacity: opacity})
+
+	return switcherView{entries: entries}, nil
+}
+
+type switcherEntry struct {
+	id      int
+	child   Widget
+	opacity float64
+}
+
+// Stacks its entries on top of each other, from oldest to newest, with each faded to its opacity
+type switcherView struct {
+	Widget
+
+	entries []switcherEntry
+}
+
+var _ RenderWidget = switcherView{}
+
+func (w switcherView) Layout(context LayoutContext) (Size, error) {
+	size := context.Constraints.Min
+
+	for _, entry := range w.entries {
+		if entry.child == nil {
+			continue
+		}
+
+		childSize, err := context.LayoutChild(entry.id, Opacity{
+			Child: entry.child,
+			Value: entry.opacity,
+		}, context.Constraints)
+		if err != nil {
+			return Size{}, err
+		}
+		err = context.PositionChild(entry.id, Pos{})
+		if err != nil {
+			return Size{}, err
+		}
+
+		size.Width = DimensionInt(max(size.Width.Int(), childSize.Width.Int()))
+		size.Height = DimensionInt(max(size.Height.Int(), childSize.Height.Int()))
+	}
+
+	return size, nil
+}
+
+func (w switcherView) Paint(context PaintContext) error {
+	return nil
+}
@@ -0,0 +1,263 @@
+package goatw
+
+import (
+	"fmt"
+
+	. "github.com/jwr1/goat"
+)
+
+// The characters a Border is drawn with
+type BorderStyle struct {
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+}
+
+var (
+	BorderStyleSingle  = BorderStyle{"─", "│", "┌", "┐", "└", "┘"}
+	BorderStyleDouble  = BorderStyle{"═", "║", "╔", "╗", "╚", "╝"}
+	BorderStyleRounded = BorderStyle{"─", "│", "╭", "╮", "╰", "╯"}
+	BorderStyleHeavy   = BorderStyle{"━", "┃", "┏", "┓", "┗", "┛"}
+	BorderStyleDashed  = BorderStyle{"╌", "╎", "┌", "┐", "└", "┘"}
+	// Uses only ASCII characters, for terminals and fonts without box drawing characters
+	BorderStyleASCII = BorderStyle{"-", "|", "+", "+", "+", "+"}
+)
+
+// A set of sides of a Border
+type BorderSides int
+
+const (
+	BorderSideTop BorderSides = 1 << iota
+	BorderSideRight
+	BorderSideBottom
+	BorderSideLeft
+
+	BorderSidesAll = BorderSideTop | BorderSideRight | BorderSideBottom | BorderSideLeft
+)
+
+func (s BorderSides) Has(side BorderSides) bool {
+	return s&side == side
+}
+
+// Colors of each side of a Border, where a side falls back to the Border's Color when its color is zero
+type BorderColors struct {
+	Top    Color
+	Right  Color
+	Bottom Color
+	Left   Color
+}
+
+// Draws a frame around its child, with an optional title in the top edge and footer in the bottom edge.
+//
+// Borders that are children of a Grid with CollapseBorders share their edges, which meet with the right junctions.
+type Border struct {
+	Widget
+
+	Child Widget
+	// Characters the border is drawn with, defaulting to BorderStyleSingle
+	Style BorderStyle
+	// Sides that are drawn, defaulting to BorderSidesAll when zero
+	Sides BorderSides
+	Color Color
+	// Colors of individual sides, where the corners take the color of the top or bottom side
+	SideColors BorderColors
+
+	Title       string
+	TitleAlign  TextAlign
+	Footer      string
+	FooterAlign TextAlign
+}
+
+var _ RenderWidget = Border{}
+
+func (w Border) style() BorderStyle {
+	if w.Style == (BorderStyle{}) {
+		return BorderStyleSingle
+	}
+	return w.Style
+}
+
+func (w Border) sides() BorderSides {
+	if w.Sides == 0 {
+		return BorderSidesAll
+	}
+	return w.Sides
+}
+
+// Returns the color of each side, filling in the ones that are zero with Color
+func (w Border) sideColors() BorderColors {
+	colors := w.SideColors
+	for _, color := range []*Color{&colors.Top, &colors.Right, &colors.Bottom, &colors.Left} {
+		if *color == (Color{}) {
+			*color = w.Color
+		}
+	}
+	return colors
+}
+
+func (w Border) insets() EdgeInserts {
+	insets := EdgeInserts{}
+	sides := w.sides()
+	if sides.Has(BorderSideTop) {
+		insets.Top = 1
+	}
+	if sides.Has(BorderSideRight) {
+		insets.Right = 1
+	}
+	if sides.Has(BorderSideBottom) {
+		insets.Bottom = 1
+	}
+	if sides.Has(BorderSideLeft) {
+		insets.Left = 1
+	}
+	return insets
+}
+
+func (w Border) Layout(context LayoutContext) (Size, error) {
+	insets := w.insets()
+
+	if w.Child == nil {
+		return SizeZero.AddEdgeInserts(insets).Clamp(context.Constraints), nil
+	}
+
+	childConstrains := Constraints{
+		Min: context.Constraints.Min.SubEdgeInserts(insets),
+		Max: context.Constraints.Max.SubEdgeInserts(insets),
+	}
+	if childConstrains.Min.HasNeg() {
+		childConstrains.Min = SizeZero
+	}
+	if childConstrains.Max.HasNeg() {
+		return Size{}, fmt.Errorf("not enough space for border given constraints")
+	}
+
+	childSize, err := context.LayoutChild(0, w.Child, childConstrains)
+	if err != nil {
+		return Size{}, err
+	}
+
+	err = context.PositionChild(0, Pos{
+		X: insets.Left,
+		Y: insets.Top,
+	})
+	if err != nil {
+		return Size{}, err
+	}
+
+	return childSize.AddEdgeInserts(insets), nil
+}
+
+func (w Border) Paint(context PaintContext) error {
+	width := context.Size.Width.Int()
+	height := context.Size.Height.Int()
+	style := w.style()
+	sides := w.sides()
+
+	colors := w.sideColors()
+	setCell := func(x, y int, grapheme string, color Color) {
+		context.Canvas.SetCell(x, y, Cell{
+			Grapheme:   grapheme,
+			Foreground: color,
+		})
+	}
+
+	// Picks the corner character when both of its sides are drawn, or continues the side that is drawn
+	corner := func(horizontalSide, verticalSide BorderSides, cornerGrapheme string) string {
+		switch {
+		case sides.Has(horizontalSide) && sides.Has(verticalSide):
+			return cornerGrapheme
+		case sides.Has(horizontalSide):
+			return style.Horizontal
+		default:
+			return style.Vertical
+		}
+	}
+
+	if sides.Has(BorderSideTop) {
+		for x := 0; x < width; x++ {
+			setCell(x, 0, style.Horizontal, colors.Top)
+		}
+	}
+	if sides.Has(BorderSideBottom) {
+		for x := 0; x < width; x++ {
+			setCell(x, height-1, style.Horizontal, colors.Bottom)
+		}
+	}
+	if sides.Has(BorderSideLeft) {
+		for y := 0; y < height; y++ {
+			setCell(0, y, style.Vertical, colors.Left)
+		}
+	}
+	if sides.Has(BorderSideRight) {
+		for y := 0; y < height; y++ {
+			setCell(width-1, y, style.Vertical, colors.Right)
+		}
+	}
+
+	// Corners take the color of the top or bottom side, unless only the left or right side is drawn
+	cornerColor := func(horizontalSide BorderSides, horizontalColor, verticalColor Color) Color {
+		if sides.Has(horizontalSide) {
+			return horizontalColor
+		}
+		return verticalColor
+	}
+
+	if sides.Has(BorderSideTop) || sides.Has(BorderSideLeft) {
+		setCell(0, 0, corner(BorderSideTop, BorderSideLeft, style.TopLeft), cornerColor(BorderSideTop, colors.Top, colors.Left))
+	}
+	if sides.Has(BorderSideTop) || sides.Has(BorderSideRight) {
+		setCell(width-1, 0, corner(BorderSideTop, BorderSideRight, style.TopRight), cornerColor(BorderSideTop, colors.Top, colors.Right))
+	}
+	if sides.Has(BorderSideBottom) || sides.Has(BorderSideLeft) {
+		setCell(0, height-1, corner(BorderSideBottom, BorderSideLeft, style.BottomLeft), cornerColor(BorderSideBottom, colors.Bottom, colors.Left))
+	}
+	if sides.Has(BorderSideBottom) || sides.Has(BorderSideRight) {
+		setCell(width-1, height-1, corner(BorderSideBottom, BorderSideRight, style.BottomRight), cornerColor(BorderSideBottom, colors.Bottom, colors.Right))
+	}
+
+	if sides.Has(BorderSideTop) {
+		w.paintLabel(&context.Canvas, w.Title, w.TitleAlign, 0, width, colors.Top)
+	}
+	if sides.Has(BorderSideBottom) {
+		w.paintLabel(&context.Canvas, w.Footer, w.FooterAlign, height-1, width, colors.Bottom)
+	}
+
+	return nil
+}
+
+// Draws the text surrounded by spaces within the edge at row y, keeping the corners visible
+func (w Border) paintLabel(canvas *Canvas, text string, align TextAlign, y, width int, color Color) {
+	if text == "" {
+		return
+	}
+
+	// Leave room for a corner and a space on each side
+	maxWidth := width - 4
+	label := splitGraphemes(text)
+	if lineWidth(label) > maxWidth {
+		label = ellipsize(label, maxWidth)
+	}
+	if len(label) == 0 {
+		return
+	}
+
+	labelWidth := lineWidth(label) + 2
+	x := 1
+	switch align {
+	case TextAlignCenter:
+		x = (width - labelWidth) / 2
+	case TextAlignRight:
+		x = width - 1 - labelWidth
+	}
+
+	canvas.SetCell(x, y, Cell{Grapheme: " ", Foreground: color})
+	x++
+	for _, g := range label {
+		canvas.SetCell(x, y, Cell{Grapheme: g.paintText(), Width: g.width, Foreground: color})
+		x += g.width
+	}
+	canvas.SetCell(x, y, Cell{Grapheme: " ", Foreground: color})
+}
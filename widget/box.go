@@ -0,0 +1,160 @@
+package goatw
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/jwr1/goat"
+)
+
+// Adds extra constraints to its child, on top of the ones from its parent
+type ConstrainedBox struct {
+	Widget
+
+	Child Widget
+	// Constraints for the child, which are narrowed to fit within the parent's constraints, where a zero maximum width or height is unbounded
+	Constraints Constraints
+	// Ignores the minimum size from the parent, so the child can be smaller than the parent wants.
+	// The box still follows the parent's constraints, placing the child at its top left.
+	Loose bool
+}
+
+var _ RenderWidget = ConstrainedBox{}
+
+func (w ConstrainedBox) Layout(context LayoutContext) (Size, error) {
+	parentConstraints := context.Constraints
+	if w.Loose {
+		parentConstraints = parentConstraints.Loosen()
+	}
+
+	childSize, err := context.LayoutChild(0, w.Child, w.Constraints.Enforce(parentConstraints))
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+
+	return childSize.Clamp(context.Constraints), nil
+}
+
+func (w ConstrainedBox) Paint(context PaintContext) error {
+	return nil
+}
+
+// Sizes its child to a ratio of width to height, as it appears on screen.
+// Cells are about twice as tall as they are wide, so a Ratio of 1 gives a child twice as many columns as rows, like SizeSquareVisual.
+type AspectRatio struct {
+	Widget
+
+	Child Widget
+	// Visual width divided by visual height
+	Ratio float64
+}
+
+var _ RenderWidget = AspectRatio{}
+
+// Number of columns that take up the same visual length as one row
+const cellAspectRatio = 2
+
+func (w AspectRatio) Layout(context LayoutContext) (Size, error) {
+	if w.Ratio <= 0 {
+		return Size{}, fmt.Errorf("AspectRatio must have a positive Ratio, got %v", w.Ratio)
+	}
+
+	columnsPerRow := w.Ratio * cellAspectRatio
+	maxSize := context.Constraints.Max
+
+	// Take up the full width if possible, and otherwise the full height
+	var size Size
+	switch {
+	case !maxSize.Width.IsInf():
+		size = SizeInt(maxSize.Width.Int(), int(math.Round(float64(maxSize.Width.Int())/columnsPerRow)))
+		if size.Height.Int() > maxSize.Height.Int() {
+			size = SizeInt(int(math.Round(float64(maxSize.Height.Int())*columnsPerRow)), maxSize.Height.Int())
+		}
+	case !maxSize.Height.IsInf():
+		size = SizeInt(int(math.Round(float64(maxSize.Height.Int())*columnsPerRow)), maxSize.Height.Int())
+	default:
+		return Size{}, fmt.Errorf("AspectRatio cannot be laid out with unbounded constraints")
+	}
+	size = size.Clamp(context.Constraints)
+
+	if w.Child != nil {
+		_, err := context.LayoutChild(0, w.Child, size.TightConstraints())
+		if err != nil {
+			return Size{}, err
+		}
+		err = context.PositionChild(0, Pos{})
+		if err != nil {
+			return Size{}, err
+		}
+	}
+
+	return size, nil
+}
+
+func (w AspectRatio) Paint(context PaintContext) error {
+	return nil
+}
+
+// Sizes its child to a fraction of the space available, and places it according to the alignment
+type FractionallySizedBox struct {
+	Widget
+
+	Child     Widget
+	Alignment Alignment
+	// Fraction of the maximum width given to the child, or if zero, the child is given the parent's width constraints
+	WidthFactor float64
+	// Fraction of the maximum height given to the child, or if zero, the child is given the parent's height constraints
+	HeightFactor float64
+}
+
+var _ RenderWidget = FractionallySizedBox{}
+
+func (w FractionallySizedBox) Layout(context LayoutContext) (Size, error) {
+	childConstraints := context.Constraints
+	maxSize := context.Constraints.Max
+
+	if w.WidthFactor > 0 {
+		if maxSize.Width.IsInf() {
+			return Size{}, fmt.Errorf("FractionallySizedBox cannot use a WidthFactor with unbounded width")
+		}
+		width := DimensionInt(int(math.Round(float64(maxSize.Width.Int()) * w.WidthFactor)))
+		childConstraints.Min.Width = width
+		childConstraints.Max.Width = width
+	}
+	if w.HeightFactor > 0 {
+		if maxSize.Height.IsInf() {
+			return Size{}, fmt.Errorf("FractionallySizedBox cannot use a HeightFactor with unbounded height")
+		}
+		height := DimensionInt(int(math.Round(float64(maxSize.Height.Int()) * w.HeightFactor)))
+		childConstraints.Min.Height = height
+		childConstraints.Max.Height = height
+	}
+
+	childSize, err := context.LayoutChild(0, w.Child, childConstraints)
+	if err != nil {
+		return Size{}, err
+	}
+
+	size := childSize.Clamp(context.Constraints)
+	if w.WidthFactor > 0 {
+		size.Width = maxSize.Width
+	}
+	if w.HeightFactor > 0 {
+		size.Height = maxSize.Height
+	}
+
+	err = context.PositionChild(0, w.Alignment.Offset(size, childSize))
+	if err != nil {
+		return Size{}, err
+	}
+
+	return size, nil
+}
+
+func (w FractionallySizedBox) Paint(context PaintContext) error {
+	return nil
+}
@@ -1,6 +1,8 @@
 package goatw
 
 import (
+	"sync/atomic"
+	"time"
 	"unicode/utf8"
 
 	. "github.com/jwr1/goat"
@@ -8,6 +10,72 @@ import (
 	"github.com/gdamore/tcell/v2"
 )
 
+// Reports whether the key event is for the key, which is either a single character, or the name of a key from tcell.KeyNames such as "Enter"
+func keyMatches(event *tcell.EventKey, key string) bool {
+	if event.Key() == tcell.KeyRune {
+		r, size := utf8.DecodeRuneInString(key)
+		return key != "" && size == len(key) && r == event.Rune()
+	}
+
+	return key == tcell.KeyNames[event.Key()]
+}
+
+// A hook for controls that are activated by clicking them, by pressing Enter or Space while they are focused, or by pressing one of the key activators at any time.
+// Clicking also moves focus to the control if it is focusable. Returns the mouse state of the control, and whether it is focused.
+func useActivatable(focusable, autofocus bool, keyActivators []string, onActivate func()) (ButtonState, bool) {
+	buttonState, setButtonState := UseState(ButtonStateIdle)
+	focused, requestFocus := UseOptionalFocus(focusable, autofocus)
+	// Whether the primary button was pressed over the control, which is read from the listener rather than buttonState, since that is only updated by the next build
+	pressed := UseRefFunc(func() *atomic.Bool {
+		return &atomic.Bool{}
+	})
+
+	UseEvent(func(context EventContext) {
+		switch event := context.Event.(type) {
+		case *tcell.EventKey:
+			if onActivate == nil {
+				return
+			}
+
+			if context.Focused && (event.Key() == tcell.KeyEnter || (event.Key() == tcell.KeyRune && event.Rune() == ' ')) {
+				onActivate()
+				return
+			}
+
+			for _, keyActivator := range keyActivators {
+				if keyMatches(event, keyActivator) {
+					onActivate()
+					return
+				}
+			}
+		case *tcell.EventMouse:
+			if !context.Contains(event.Position()) {
+				pressed.Store(false)
+				setButtonState(ButtonStateIdle)
+				return
+			}
+
+			if event.Buttons()&tcell.ButtonPrimary != 0 {
+				pressed.Store(true)
+				setButtonState(ButtonStateActive)
+				return
+			}
+
+			setButtonState(ButtonStateHover)
+			if pressed.Swap(false) {
+				if focusable {
+					requestFocus()
+				}
+				if onActivate != nil {
+					onActivate()
+				}
+			}
+		}
+	})
+
+	return buttonState, focused
+}
+
 type ButtonState int
 
 const (
@@ -23,70 +91,36 @@ type Button struct {
 	Padding       EdgeInserts
 	OnActivate    func()
 	KeyActivators []string
+	// Lets the button be reached with Tab, and activated with Enter or Space while it has focus
+	Focusable bool
+	// Focuses the button when it is first shown, which also makes it focusable
+	Autofocus bool
 }
 
 var _ StateWidget = Button{}
 
-func (w Button) Build() (Widget, error) {
-	buttonState, setButtonState := UseState(ButtonStateIdle)
-
-	UseEvent(func(context EventContext) {
-		switch event := context.Event.(type) {
-		case *tcell.EventKey:
-			if w.OnActivate == nil || len(w.KeyActivators) == 0 {
-				return
-			}
+// How long a Button takes to change color when it is hovered, pressed or focused
+const buttonColorDuration = 100 * time.Millisecond
 
-			if event.Key() == tcell.KeyRune {
-				for _, keyActivator := range w.KeyActivators {
-					r, _ := utf8.DecodeRuneInString(keyActivator)
-					if r == event.Rune() {
-						w.OnActivate()
-					}
-				}
-			} else {
-				keyName := tcell.KeyNames[event.Key()]
-				for _, keyActivator := range w.KeyActivators {
-					if keyActivator == keyName {
-						w.OnActivate()
-					}
-				}
-			}
-		case *tcell.EventMouse:
-			x, y := event.Position()
-			intersects := context.RenderPos.X <= x && x < context.RenderPos.X+context.RenderSize.Width.Int() &&
-				context.RenderPos.Y <= y && y < context.RenderPos.Y+context.RenderSize.Height.Int()
-			if intersects {
-				setButtonState(ButtonStateHover)
-
-				if event.Buttons()&tcell.ButtonPrimary != 0 {
-					setButtonState(ButtonStateActive)
-				} else {
-					setButtonState(ButtonStateHover)
-					if buttonState == ButtonStateActive {
-						if w.OnActivate != nil {
-							w.OnActivate()
-						}
-					}
-				}
-			} else {
-				setButtonState(ButtonStateIdle)
-			}
-		}
-	})
+func (w Button) Build() (Widget, error) {
+	buttonState, focused := useActivatable(w.Focusable || w.Autofocus, w.Autofocus, w.KeyActivators, w.OnActivate)
 
 	var bgColor Color
 	switch buttonState {
 	case ButtonStateIdle:
 		bgColor = ColorRGB(100, 0, 0)
+		if focused {
+			bgColor = ColorRGB(150, 0, 0)
+		}
 	case ButtonStateHover:
 		bgColor = ColorRGB(200, 0, 0)
 	case ButtonStateActive:
 		bgColor = ColorRGB(255, 0, 0)
 	}
 
-	return Background{
+	return AnimatedBackground{
 		Background: bgColor,
+		Duration:   buttonColorDuration,
 		Child: Padding{
 			Padding: w.Padding,
 			Child:   Text{Text: w.Label},
@@ -0,0 +1,116 @@
+package goatw
+
+import (
+	"sync"
+
+	. "github.com/jwr1/goat"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A button shown at the bottom of a Dialog
+type DialogAction struct {
+	Label         string
+	OnActivate    func()
+	KeyActivators []string
+}
+
+// A box with a title, a body and a row of action buttons, meant to be shown with ShowDialog.
+// The first action takes focus when the dialog is shown.
+type Dialog struct {
+	Widget
+
+	Title   string
+	Body    Widget
+	Actions []DialogAction
+	// Called when Escape is pressed
+	OnDismiss func()
+}
+
+var _ StateWidget = Dialog{}
+
+func (w Dialog) Build() (Widget, error) {
+	UseEvent(func(context EventContext) {
+		event, ok := context.Event.(*tcell.EventKey)
+		if ok && event.Key() == tcell.KeyEscape && w.OnDismiss != nil {
+			w.OnDismiss()
+		}
+	})
+
+	actions := []Widget{}
+	for i, action := range w.Actions {
+		if i > 0 {
+			actions = append(actions, SizedBox{Width: 1})
+		}
+		actions = append(actions, Button{
+			Label:         action.Label,
+			Padding:       EdgeInsertsSymmetric(0, 1),
+			OnActivate:    action.OnActivate,
+			KeyActivators: action.KeyActivators,
+			Focusable:     true,
+			Autofocus:     i == 0,
+		})
+	}
+
+	children := []Widget{}
+	if w.Title != "" {
+		children = append(children,
+			RichText{Text: TextSpan{Text: w.Title, Style: TextStyle{Bold: true}}},
+			SizedBox{Height: 1},
+		)
+	}
+	if w.Body != nil {
+		children = append(children, w.Body, SizedBox{Height: 1})
+	}
+	children = append(children, Row{
+		MainAxisShrinkWrap: true,
+		Children:           actions,
+	})
+
+	return Background{
+		Background: ColorRGB(40, 40, 40),
+		Child: Padding{
+			Padding: EdgeInsertsSymmetric(1, 2),
+			Child: Column{
+				MainAxisShrinkWrap: true,
+				Children:           children,
+			},
+		},
+	}, nil
+}
+
+// The color drawn over the app behind a dialog
+var dialogBackdropColor = Color{R: 0, G: 0, B: 0, A: 0x99}
+
+// Shows a modal over the whole app, which dims everything behind it and stops it from receiving events.
+// The builder is given a function that closes the dialog, whose result is then sent to the returned channel.
+//
+// A widget's event listeners are called one at a time, so waiting on the channel from within one stops that widget receiving events until the dialog closes; start a goroutine instead.
+func ShowDialog[T any](overlay Overlay, builder func(close func(result T)) Widget) <-chan T {
+	results := make(chan T, 1)
+
+	// The entry is inserted before the builder is called, so that the dialog can be closed from within the builder.
+	// Updating it after it has been removed does nothing, since it stays removed.
+	entry := overlay.Insert(Background{
+		Background: dialogBackdropColor,
+		Child:      SizedBox{},
+	}, OverlayBarrier())
+
+	var once sync.Once
+	closeDialog := func(result T) {
+		once.Do(func() {
+			entry.Remove()
+			results <- result
+			close(results)
+		})
+	}
+
+	entry.Update(Background{
+		Background: dialogBackdropColor,
+		Child: Center{
+			Child: builder(closeDialog),
+		},
+	})
+
+	return results
+}
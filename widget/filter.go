@@ -0,0 +1,162 @@
+package goatw
+
+import (
+	"math"
+
+	. "github.com/jwr1/goat"
+)
+
+// Fades its child by multiplying the alpha of every cell it draws by the value, from 0 for invisible to 1 for unchanged.
+//
+// A color with no alpha stands for the terminal's default color, which has no alpha of its own to fade, so text in the default color is faded from DefaultForeground instead.
+// This is an approximation, which only matches the terminal when DefaultForeground is set to its actual text color.
+type Opacity struct {
+	Widget
+
+	Child Widget
+	Value float64
+}
+
+var _ CompositeWidget = Opacity{}
+
+func (w Opacity) Layout(context LayoutContext) (Size, error) {
+	size, err := context.LayoutChild(0, w.Child, context.Constraints)
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+	return size, nil
+}
+
+func (w Opacity) Paint(context PaintContext) error {
+	return nil
+}
+
+func (w Opacity) Composite(canvas *Canvas) error {
+	if w.Value >= 1 {
+		return nil
+	}
+
+	value := max(w.Value, 0)
+	canvas.TransformCells(func(x, y int, cell Cell) Cell {
+		// Text in the terminal's default color is faded too, taking DefaultForeground as a stand in for the color the terminal uses
+		if cell.Foreground.A == 0 {
+			cell.Foreground = DefaultForeground
+		}
+
+		cell.Foreground.A = uint8(float64(cell.Foreground.A) * value)
+		cell.Background.A = uint8(float64(cell.Background.A) * value)
+
+		// A foreground with no alpha left would be drawn in the terminal's default color, so the text is removed instead
+		if cell.Foreground.A == 0 && (cell.Grapheme != "" || cell.Continuation) {
+			cell.Grapheme = " "
+			cell.Width = 1
+			cell.Continuation = false
+			cell.TextStyle = nil
+		}
+
+		return cell
+	})
+
+	return nil
+}
+
+// Changes the colors of everything its child draws, such as to make a disabled control or an inactive panel look dimmed.
+// The filters are applied in the order they are listed in.
+//
+// Colors that are not set, which are drawn in the terminal's default colors, are left as they are,
+// except that text in the default foreground color is drawn with the dim text style when Dim is set.
+type ColorFilter struct {
+	Widget
+
+	Child Widget
+	// Removes the saturation of colors, from 0 for unchanged to 1 for fully gray
+	Grayscale float64
+	// Mixes colors with the RGB of the tint, by the amount given by its alpha
+	Tint   Color
+	Invert bool
+	// Darkens colors towards black, from 0 for unchanged to 1 for fully black
+	Dim float64
+}
+
+var _ CompositeWidget = ColorFilter{}
+
+func (w ColorFilter) Layout(context LayoutContext) (Size, error) {
+	size, err := context.LayoutChild(0, w.Child, context.Constraints)
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+	return size, nil
+}
+
+func (w ColorFilter) Paint(context PaintContext) error {
+	return nil
+}
+
+func (w ColorFilter) Composite(canvas *Canvas) error {
+	canvas.TransformCells(func(x, y int, cell Cell) Cell {
+		cell.Foreground = w.filter(cell.Foreground)
+		cell.Background = w.filter(cell.Background)
+
+		if w.Dim > 0 && cell.Foreground.A == 0 {
+			style := CellTextStyle{}
+			if cell.TextStyle != nil {
+				style = *cell.TextStyle
+			}
+			style.Dim = true
+			cell.TextStyle = &style
+		}
+
+		return cell
+	})
+
+	return nil
+}
+
+// Returns the color with each of the filters applied, keeping its alpha
+func (w ColorFilter) filter(color Color) Color {
+	if color.A == 0 {
+		return color
+	}
+
+	r, g, b := float64(color.R), float64(color.G), float64(color.B)
+
+	if w.Grayscale > 0 {
+		amount := min(w.Grayscale, 1)
+		luma := 0.2126*r + 0.7152*g + 0.0722*b
+		r += (luma - r) * amount
+		g += (luma - g) * amount
+		b += (luma - b) * amount
+	}
+
+	if w.Tint.A > 0 {
+		amount := float64(w.Tint.A) / 0xff
+		r += (float64(w.Tint.R) - r) * amount
+		g += (float64(w.Tint.G) - g) * amount
+		b += (float64(w.Tint.B) - b) * amount
+	}
+
+	if w.Invert {
+		r, g, b = 0xff-r, 0xff-g, 0xff-b
+	}
+
+	if w.Dim > 0 {
+		amount := 1 - min(w.Dim, 1)
+		r *= amount
+		g *= amount
+		b *= amount
+	}
+
+	channel := func(c float64) uint8 {
+		return uint8(min(max(math.Round(c), 0), 0xff))
+	}
+
+	return Color{R: channel(r), G: channel(g), B: channel(b), A: color.A}
+}
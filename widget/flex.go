@@ -75,34 +75,72 @@ func (w Flex) Layout(context LayoutContext) (Size, error) {
 		}
 	}
 
-	remainingSpace := mainAxisSize(context.Constraints.Max)
+	maxMainAxisSize := mainAxisSize(context.Constraints.Max)
+	remainingSpace := maxMainAxisSize
 	childrenSizes := make([]Size, len(w.Children))
 	childrenCrossAxisPos := make([]int, len(w.Children))
 	finalCrossAxisSize := crossAxisSize(context.Constraints.Min)
+	childMinCrossAxisSize := 0
 	if w.CrossAxisAlignment == CrossAxisAlignmentStretch {
 		finalCrossAxisSize = crossAxisSize(context.Constraints.Max)
+		childMinCrossAxisSize = crossAxisSize(context.Constraints.Max)
 	}
 
-	for i, child := range w.Children {
-		childMinSize := Size{}
-		if w.CrossAxisAlignment == CrossAxisAlignmentStretch {
-			childMinSize = sizeFromAxes(0, crossAxisSize(context.Constraints.Max))
+	layoutChild := func(i int, minMainAxisSize, maxMainAxisSize int) error {
+		childSize, err := context.LayoutChild(i, w.Children[i], Constraints{
+			Min: sizeFromAxes(minMainAxisSize, childMinCrossAxisSize),
+			Max: sizeFromAxes(maxMainAxisSize, crossAxisSize(context.Constraints.Max)),
+		})
+		if err != nil {
+			return err
+		}
+
+		childrenSizes[i] = childSize
+		remainingSpace -= mainAxisSize(childSize)
+		if crossAxisSize(childSize) > finalCrossAxisSize {
+			finalCrossAxisSize = crossAxisSize(childSize)
 		}
+		return nil
+	}
 
-		childConstrains := Constraints{
-			Min: childMinSize,
-			Max: sizeFromAxes(remainingSpace, crossAxisSize(context.Constraints.Max)),
+	// Inflexible children are laid out first, in order, with each being given the space left by the ones before it.
+	// Flexible children can only share out the space that is left when the main axis is bounded.
+	mainAxisBounded := maxMainAxisSize != DimensionInfinite.Int()
+	flexChildren := make([]FlexParentData, len(w.Children))
+	totalFlex := 0
+	for i, child := range w.Children {
+		if parentData, ok := ParentDataOf(child).(FlexParentData); ok && parentData.Flex > 0 && mainAxisBounded {
+			flexChildren[i] = parentData
+			totalFlex += parentData.Flex
+			continue
 		}
 
-		childSize, err := context.LayoutChild(i, child, childConstrains)
+		err := layoutChild(i, 0, remainingSpace)
 		if err != nil {
 			return Size{}, err
 		}
+	}
 
-		childrenSizes[i] = childSize
-		remainingSpace -= mainAxisSize(childSize)
-		if crossAxisSize(childSize) > finalCrossAxisSize {
-			finalCrossAxisSize = crossAxisSize(childSize)
+	// Then the remaining space is divided between flexible children according to their flex factors, with rounding errors carried over to later children
+	freeSpace := max(remainingSpace, 0)
+	flexSoFar := 0
+	for i, parentData := range flexChildren {
+		if parentData.Flex == 0 {
+			continue
+		}
+
+		start := freeSpace * flexSoFar / totalFlex
+		flexSoFar += parentData.Flex
+		allocatedSpace := freeSpace*flexSoFar/totalFlex - start
+
+		minMainAxisSize := 0
+		if parentData.Fit == FlexFitTight {
+			minMainAxisSize = allocatedSpace
+		}
+
+		err := layoutChild(i, minMainAxisSize, allocatedSpace)
+		if err != nil {
+			return Size{}, err
 		}
 	}
 
@@ -117,9 +155,9 @@ func (w Flex) Layout(context LayoutContext) (Size, error) {
 		}
 	}
 
-	finalMainAxisSize := mainAxisSize(context.Constraints.Max)
+	finalMainAxisSize := maxMainAxisSize
 	if w.MainAxisShrinkWrap {
-		minMainAxisSize := mainAxisSize(context.Constraints.Max) - remainingSpace
+		minMainAxisSize := maxMainAxisSize - remainingSpace
 		finalMainAxisSize = max(minMainAxisSize, mainAxisSize(context.Constraints.Min))
 		remainingSpace = finalMainAxisSize - minMainAxisSize
 	}
@@ -191,6 +229,91 @@ func (w Flex) Paint(context PaintContext) error {
 	return nil
 }
 
+// How a flexible child of a Flex fills the space it is given
+type FlexFit int
+
+const (
+	// The child can be smaller than its share of the space
+	FlexFitLoose FlexFit = iota
+	// The child is forced to fill its share of the space
+	FlexFitTight
+)
+
+// The parent data of Flexible and Expanded, which is read by Flex
+type FlexParentData struct {
+	Flex int
+	Fit  FlexFit
+}
+
+// Gives its child a share of the space left in a Row, Column or Flex after the inflexible children are laid out, in proportion to its Flex factor.
+// It must be a direct child of the Flex.
+type Flexible struct {
+	Widget
+
+	Child Widget
+	// How large the child's share of the remaining space is, relative to other flexible children, defaulting to 1 when zero
+	Flex int
+	Fit  FlexFit
+}
+
+var _ RenderWidget = Flexible{}
+var _ ParentDataWidget = Flexible{}
+
+func (w Flexible) ParentData() any {
+	return FlexParentData{
+		Flex: max(w.Flex, 1),
+		Fit:  w.Fit,
+	}
+}
+
+func (w Flexible) Layout(context LayoutContext) (Size, error) {
+	return layoutFlexChild(context, w.Child)
+}
+
+func (w Flexible) Paint(context PaintContext) error {
+	return nil
+}
+
+// A Flexible that forces its child to fill its whole share of the remaining space
+type Expanded struct {
+	Widget
+
+	Child Widget
+	// How large the child's share of the remaining space is, relative to other flexible children, defaulting to 1 when zero
+	Flex int
+}
+
+var _ RenderWidget = Expanded{}
+var _ ParentDataWidget = Expanded{}
+
+func (w Expanded) ParentData() any {
+	return FlexParentData{
+		Flex: max(w.Flex, 1),
+		Fit:  FlexFitTight,
+	}
+}
+
+func (w Expanded) Layout(context LayoutContext) (Size, error) {
+	return layoutFlexChild(context, w.Child)
+}
+
+func (w Expanded) Paint(context PaintContext) error {
+	return nil
+}
+
+// Lays out the child of a Flexible or Expanded with the constraints given by the Flex
+func layoutFlexChild(context LayoutContext, child Widget) (Size, error) {
+	size, err := context.LayoutChild(0, child, context.Constraints)
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+	return size, nil
+}
+
 type Row struct {
 	Widget
 
@@ -244,30 +367,12 @@ type Center struct {
 var _ RenderWidget = Center{}
 
 func (w Center) Layout(context LayoutContext) (Size, error) {
-	childSize, err := context.LayoutChild(0, w.Child, Constraints{
-		Max: context.Constraints.Max,
-	})
-	if err != nil {
-		return Size{}, err
-	}
-
-	size := context.Constraints.Max
-	if w.WidthFactor >= 1 {
-		size.Width = DimensionInt(int(float64(childSize.Width.Int()) * w.WidthFactor))
-	}
-	if w.HeightFactor >= 1 {
-		size.Height = DimensionInt(int(float64(childSize.Height.Int()) * w.HeightFactor))
-	}
-	size = size.Clamp(context.Constraints)
-
-	remainingSize := size.Sub(childSize)
-
-	context.PositionChild(0, Pos{
-		X: remainingSize.Width.Int() / 2,
-		Y: remainingSize.Height.Int() / 2,
-	})
-
-	return size, nil
+	return Align{
+		Child:        w.Child,
+		Alignment:    AlignmentCenter,
+		WidthFactor:  w.WidthFactor,
+		HeightFactor: w.HeightFactor,
+	}.Layout(context)
 }
 
 func (w Center) Paint(context PaintContext) error {
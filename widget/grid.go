@@ -0,0 +1,425 @@
+package goatw
+
+import (
+	"fmt"
+
+	. "github.com/jwr1/goat"
+)
+
+type gridBreadthKind int
+
+const (
+	gridBreadthFixed gridBreadthKind = iota
+	gridBreadthAuto
+	gridBreadthFr
+)
+
+type gridBreadth struct {
+	kind  gridBreadthKind
+	value int
+}
+
+// The size of a column or row of a Grid, which is created with GridFixed, GridFr, GridAuto or GridMinMax
+type GridTrack struct {
+	min gridBreadth
+	max gridBreadth
+}
+
+// A track that is always the given number of cells
+func GridFixed(cells int) GridTrack {
+	return GridTrack{
+		min: gridBreadth{kind: gridBreadthFixed, value: cells},
+		max: gridBreadth{kind: gridBreadthFixed, value: cells},
+	}
+}
+
+// A track that takes a share of the space left over by the other tracks, in proportion to its fraction.
+// It can shrink to nothing, so use GridMinMax(GridAuto(), GridFr(n)) to keep it at least as large as its content.
+func GridFr(fraction int) GridTrack {
+	return GridTrack{
+		min: gridBreadth{kind: gridBreadthFixed, value: 0},
+		max: gridBreadth{kind: gridBreadthFr, value: max(fraction, 1)},
+	}
+}
+
+// A track that is as large as the largest child in it
+func GridAuto() GridTrack {
+	return GridTrack{
+		min: gridBreadth{kind: gridBreadthAuto},
+		max: gridBreadth{kind: gridBreadthAuto},
+	}
+}
+
+// A track that is at least as large as the minimum of min, and grows up to the maximum of max
+func GridMinMax(min, max GridTrack) GridTrack {
+	return GridTrack{
+		min: min.min,
+		max: max.max,
+	}
+}
+
+// Where a child is placed within a Grid, which is the parent data of GridItem
+type GridPlacement struct {
+	Column     int
+	Row        int
+	ColumnSpan int
+	RowSpan    int
+}
+
+// Places its child at a column and row of a Grid, counting from 0, optionally spanning more than one of each.
+// It must be a direct child of the Grid.
+type GridItem struct {
+	Widget
+
+	Child  Widget
+	Column int
+	Row    int
+	// Number of columns the child covers, defaulting to 1 when zero
+	ColumnSpan int
+	// Number of rows the child covers, defaulting to 1 when zero
+	RowSpan int
+}
+
+var _ RenderWidget = GridItem{}
+var _ ParentDataWidget = GridItem{}
+
+func (w GridItem) ParentData() any {
+	return GridPlacement{
+		Column:     w.Column,
+		Row:        w.Row,
+		ColumnSpan: max(w.ColumnSpan, 1),
+		RowSpan:    max(w.RowSpan, 1),
+	}
+}
+
+func (w GridItem) Layout(context LayoutContext) (Size, error) {
+	size, err := context.LayoutChild(0, w.Child, context.Constraints)
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+	return size, nil
+}
+
+func (w GridItem) Paint(context PaintContext) error {
+	return nil
+}
+
+// Lays out its children in aligned columns and rows, with each child filling the cells it covers.
+//
+// Children wrapped in a GridItem are placed where it says, and the rest fill the remaining cells in order, row by row.
+// Rows, and columns, are added as needed to fit every child, and are sized with GridAuto.
+type Grid struct {
+	Widget
+
+	Children []Widget
+	// Sizes of the columns, defaulting to a single GridFr(1) column when empty
+	Columns []GridTrack
+	// Sizes of the rows, where any rows beyond these are sized with GridAuto
+	Rows []GridTrack
+	// Gap between columns
+	ColumnGap int
+	// Gap between rows
+	RowGap int
+	// Overlaps neighbouring children by one cell, less any gap, and joins the box drawing lines where they meet, so that Border children share their edges
+	CollapseBorders bool
+}
+
+var _ RenderWidget = Grid{}
+var _ LineJoiningWidget = Grid{}
+
+func (w Grid) JoinsLines() bool {
+	return w.CollapseBorders
+}
+
+func (w Grid) Layout(context LayoutContext) (Size, error) {
+	columnGap, rowGap := w.ColumnGap, w.RowGap
+	if w.CollapseBorders {
+		columnGap--
+		rowGap--
+	}
+
+	columns := w.Columns
+	if len(columns) == 0 {
+		columns = []GridTrack{GridFr(1)}
+	}
+	rows := w.Rows
+
+	placements, err := w.placeChildren(len(columns))
+	if err != nil {
+		return Size{}, err
+	}
+	for _, placement := range placements {
+		for len(columns) < placement.Column+placement.ColumnSpan {
+			columns = append(columns, GridAuto())
+		}
+		for len(rows) < placement.Row+placement.RowSpan {
+			rows = append(rows, GridAuto())
+		}
+	}
+
+	columnSpans := make([]gridSpan, len(placements))
+	rowSpans := make([]gridSpan, len(placements))
+	for i, placement := range placements {
+		columnSpans[i] = gridSpan{start: placement.Column, count: placement.ColumnSpan}
+		rowSpans[i] = gridSpan{start: placement.Row, count: placement.RowSpan}
+	}
+
+	// Columns are sized first, so that the height of each child can be measured at the width it will have
+	maxSize := context.Constraints.Max
+	columnSizes, err := sizeGridTracks(columns, maxSize.Width.Int(), columnGap, columnSpans, func(i int) (int, error) {
+		size, err := context.LayoutChild(i, w.Children[i], Constraints{Max: maxSize})
+		return size.Width.Int(), err
+	})
+	if err != nil {
+		return Size{}, err
+	}
+
+	rowSizes, err := sizeGridTracks(rows, maxSize.Height.Int(), rowGap, rowSpans, func(i int) (int, error) {
+		size, err := context.LayoutChild(i, w.Children[i], Constraints{
+			Max: Size{
+				Width:  DimensionInt(columnSpans[i].size(columnSizes, columnGap)),
+				Height: maxSize.Height,
+			},
+		})
+		return size.Height.Int(), err
+	})
+	if err != nil {
+		return Size{}, err
+	}
+
+	for i, child := range w.Children {
+		cellSize := SizeInt(columnSpans[i].size(columnSizes, columnGap), rowSpans[i].size(rowSizes, rowGap))
+		_, err := context.LayoutChild(i, child, cellSize.TightConstraints())
+		if err != nil {
+			return Size{}, err
+		}
+
+		err = context.PositionChild(i, Pos{
+			X: gridSpan{count: columnSpans[i].start}.size(columnSizes, columnGap) + min(columnSpans[i].start, 1)*columnGap,
+			Y: gridSpan{count: rowSpans[i].start}.size(rowSizes, rowGap) + min(rowSpans[i].start, 1)*rowGap,
+		})
+		if err != nil {
+			return Size{}, err
+		}
+	}
+
+	return SizeInt(
+		gridSpan{count: len(columnSizes)}.size(columnSizes, columnGap),
+		gridSpan{count: len(rowSizes)}.size(rowSizes, rowGap),
+	).Clamp(context.Constraints), nil
+}
+
+func (w Grid) Paint(context PaintContext) error {
+	return nil
+}
+
+// Returns where each child is placed, putting children that are not in a GridItem into the first free cells after the ones that are
+func (w Grid) placeChildren(columnCount int) ([]GridPlacement, error) {
+	placements := make([]GridPlacement, len(w.Children))
+	occupied := map[Pos]bool{}
+
+	autoPlaced := []int{}
+	for i, child := range w.Children {
+		placement, ok := ParentDataOf(child).(GridPlacement)
+		if !ok {
+			autoPlaced = append(autoPlaced, i)
+			continue
+		}
+
+		if placement.Column < 0 || placement.Row < 0 || placement.ColumnSpan < 1 || placement.RowSpan < 1 {
+			return nil, fmt.Errorf("grid child %d has an invalid placement, column %d and row %d must not be negative, and column span %d and row span %d must be at least 1", i, placement.Column, placement.Row, placement.ColumnSpan, placement.RowSpan)
+		}
+
+		placements[i] = placement
+		for y := placement.Row; y < placement.Row+placement.RowSpan; y++ {
+			for x := placement.Column; x < placement.Column+placement.ColumnSpan; x++ {
+				occupied[Pos{X: x, Y: y}] = true
+			}
+		}
+	}
+
+	cursor := 0
+	for _, i := range autoPlaced {
+		for occupied[Pos{X: cursor % columnCount, Y: cursor / columnCount}] {
+			cursor++
+		}
+
+		placements[i] = GridPlacement{
+			Column:     cursor % columnCount,
+			Row:        cursor / columnCount,
+			ColumnSpan: 1,
+			RowSpan:    1,
+		}
+		cursor++
+	}
+
+	return placements, nil
+}
+
+// The tracks covered by a child along one axis
+type gridSpan struct {
+	start int
+	count int
+}
+
+// Returns the total size of the tracks in the span, including the gaps between them
+func (s gridSpan) size(trackSizes []int, gap int) int {
+	size := max(s.count-1, 0) * gap
+	for _, trackSize := range trackSizes[s.start : s.start+s.count] {
+		size += trackSize
+	}
+	return size
+}
+
+// Returns the size of each track along one axis.
+// The content of children that are in auto sized tracks is measured, which is also done for fractional tracks when the available space is unbounded.
+func sizeGridTracks(tracks []GridTrack, available, gap int, spans []gridSpan, measure func(i int) (int, error)) ([]int, error) {
+	bounded := available != DimensionInfinite.Int()
+
+	sizes := make([]int, len(tracks))
+	limits := make([]int, len(tracks))
+	isAutoMin := make([]bool, len(tracks))
+	isAutoMax := make([]bool, len(tracks))
+	isFr := make([]bool, len(tracks))
+	for i, track := range tracks {
+		isAutoMin[i] = track.min.kind == gridBreadthAuto
+		isAutoMax[i] = track.max.kind == gridBreadthAuto || (track.max.kind == gridBreadthFr && !bounded)
+		isFr[i] = track.max.kind == gridBreadthFr && bounded
+
+		if track.min.kind == gridBreadthFixed {
+			sizes[i] = track.min.value
+		}
+		if track.max.kind == gridBreadthFixed {
+			limits[i] = track.max.value
+		}
+	}
+
+	// Children covering a single track are measured first, then any that span several tracks grow the tracks they cover equally
+	for _, multiSpan := range []bool{false, true} {
+		for i, span := range spans {
+			if (span.count > 1) != multiSpan {
+				continue
+			}
+
+			var growSizes, growLimits []int
+			for track := span.start; track < span.start+span.count; track++ {
+				if isAutoMin[track] {
+					growSizes = append(growSizes, track)
+				}
+				if isAutoMax[track] {
+					growLimits = append(growLimits, track)
+				}
+			}
+			if len(growSizes) == 0 && len(growLimits) == 0 {
+				continue
+			}
+
+			content, err := measure(i)
+			if err != nil {
+				return nil, err
+			}
+
+			growGridTracks(sizes, growSizes, content-span.size(sizes, gap))
+			growGridTracks(limits, growLimits, content-span.size(limits, gap))
+		}
+	}
+
+	// A track's minimum wins over its maximum
+	for i := range tracks {
+		limits[i] = max(limits[i], sizes[i])
+	}
+
+	free := available - gridSpan{count: len(tracks)}.size(sizes, gap)
+	if !bounded {
+		free = 0
+		for i := range tracks {
+			sizes[i] = max(sizes[i], limits[i])
+		}
+	}
+
+	// Grow tracks up to their limits, sharing the free space equally between them
+	for free > 0 {
+		growable := []int{}
+		for i := range tracks {
+			if !isFr[i] && sizes[i] < limits[i] {
+				growable = append(growable, i)
+			}
+		}
+		if len(growable) == 0 {
+			break
+		}
+
+		for j, i := range growable {
+			share := free / (len(growable) - j)
+			if j < free%len(growable) {
+				share++
+			}
+			share = min(share, limits[i]-sizes[i], free)
+			sizes[i] += share
+			free -= share
+		}
+	}
+
+	// Then fractional tracks share what is left, except those which are already larger than their share
+	flexible := map[int]bool{}
+	frSpace := max(free, 0)
+	for i := range tracks {
+		if isFr[i] {
+			flexible[i] = true
+			frSpace += sizes[i]
+		}
+	}
+	for {
+		totalFr := 0
+		for i := range flexible {
+			totalFr += tracks[i].max.value
+		}
+		if totalFr == 0 {
+			break
+		}
+
+		changed := false
+		for i := range flexible {
+			if sizes[i]*totalFr > frSpace*tracks[i].max.value {
+				delete(flexible, i)
+				frSpace -= sizes[i]
+				changed = true
+			}
+		}
+		if changed {
+			continue
+		}
+
+		frSoFar := 0
+		for i := range tracks {
+			if !flexible[i] {
+				continue
+			}
+			start := frSpace * frSoFar / totalFr
+			frSoFar += tracks[i].max.value
+			sizes[i] = frSpace*frSoFar/totalFr - start
+		}
+		break
+	}
+
+	return sizes, nil
+}
+
+// Adds amount to the tracks, spread as equally as possible
+func growGridTracks(trackSizes []int, tracks []int, amount int) {
+	if amount <= 0 || len(tracks) == 0 {
+		return
+	}
+
+	for j, track := range tracks {
+		share := amount / len(tracks)
+		if j < amount%len(tracks) {
+			share++
+		}
+		trackSizes[track] += share
+	}
+}
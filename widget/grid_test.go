@@ -0,0 +1,129 @@
+package goatw
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/jwr1/goat"
+)
+
+func TestSizeGridTracks(t *testing.T) {
+	tests := []struct {
+		name      string
+		tracks    []GridTrack
+		available int
+		gap       int
+		spans     []gridSpan
+		// Size of the content of each child, in the same order as spans
+		content []int
+		want    []int
+	}{
+		{
+			name:      "fixed tracks",
+			tracks:    []GridTrack{GridFixed(3), GridFixed(5)},
+			available: 20,
+			want:      []int{3, 5},
+		},
+		{
+			name:      "equal fractions",
+			tracks:    []GridTrack{GridFr(1), GridFr(1)},
+			available: 10,
+			want:      []int{5, 5},
+		},
+		{
+			name:      "fractions share the space left after gaps",
+			tracks:    []GridTrack{GridFr(1), GridFr(2)},
+			available: 10,
+			gap:       1,
+			want:      []int{3, 6},
+		},
+		{
+			name:      "fractions that do not divide evenly",
+			tracks:    []GridTrack{GridFr(1), GridFr(1), GridFr(1)},
+			available: 10,
+			want:      []int{3, 3, 4},
+		},
+		{
+			name:      "fraction takes what fixed tracks leave",
+			tracks:    []GridTrack{GridFixed(4), GridFr(1)},
+			available: 10,
+			want:      []int{4, 6},
+		},
+		{
+			name:      "fraction shrinks to nothing without space",
+			tracks:    []GridTrack{GridFixed(10), GridFr(1)},
+			available: 5,
+			want:      []int{10, 0},
+		},
+		{
+			name:      "auto track fits the largest child",
+			tracks:    []GridTrack{GridAuto(), GridFr(1)},
+			available: 10,
+			spans:     []gridSpan{{start: 0, count: 1}, {start: 0, count: 1}},
+			content:   []int{2, 5},
+			want:      []int{5, 5},
+		},
+		{
+			name:      "fraction with an auto minimum keeps its content",
+			tracks:    []GridTrack{GridMinMax(GridAuto(), GridFr(1)), GridFr(1)},
+			available: 10,
+			spans:     []gridSpan{{start: 0, count: 1}},
+			content:   []int{8},
+			want:      []int{8, 2},
+		},
+		{
+			name:      "span grows the auto tracks it covers equally",
+			tracks:    []GridTrack{GridAuto(), GridAuto()},
+			available: 20,
+			gap:       1,
+			spans:     []gridSpan{{start: 0, count: 1}, {start: 0, count: 2}},
+			content:   []int{2, 7},
+			want:      []int{4, 2},
+		},
+		{
+			name:      "span does not grow fixed tracks",
+			tracks:    []GridTrack{GridFixed(2), GridAuto()},
+			available: 20,
+			spans:     []gridSpan{{start: 0, count: 2}},
+			content:   []int{6},
+			want:      []int{2, 4},
+		},
+		{
+			name:      "span over fixed tracks that are large enough",
+			tracks:    []GridTrack{GridFixed(3), GridFixed(3)},
+			available: 20,
+			spans:     []gridSpan{{start: 0, count: 2}},
+			content:   []int{10},
+			want:      []int{3, 3},
+		},
+		{
+			name:      "fractions fit their content when unbounded",
+			tracks:    []GridTrack{GridFr(1), GridFr(1)},
+			available: DimensionInfinite.Int(),
+			spans:     []gridSpan{{start: 0, count: 1}, {start: 1, count: 1}},
+			content:   []int{3, 4},
+			want:      []int{3, 4},
+		},
+		{
+			name:      "tracks grow up to their maximum",
+			tracks:    []GridTrack{GridMinMax(GridFixed(2), GridFixed(6)), GridMinMax(GridFixed(2), GridFixed(6))},
+			available: 5,
+			want:      []int{3, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := sizeGridTracks(test.tracks, test.available, test.gap, test.spans, func(i int) (int, error) {
+				return test.content[i], nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -41,7 +41,8 @@ func (w Image) Paint(context PaintContext) error {
 type ImageNetwork struct {
 	Widget
 
-	Url            string
+	Url string
+	// Builds the widget shown while the image is loading, defaulting to a Spinner
 	LoadingBuilder func() Widget
 	ErrorBuilder   func(err error) Widget
 }
@@ -80,6 +81,9 @@ func (w ImageNetwork) Build() (Widget, error) {
 	}
 
 	if data == nil {
+		if w.LoadingBuilder == nil {
+			return Spinner{}, nil
+		}
 		return w.LoadingBuilder(), nil
 	}
 
@@ -0,0 +1,373 @@
+package goatw
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/jwr1/goat"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// How a route appears when it is pushed, and disappears when it is popped
+type RouteTransition int
+
+const (
+	RouteTransitionNone RouteTransition = iota
+	// Slides the route in from the right edge
+	RouteTransitionSlide
+	// Fades the route in over the route below it
+	RouteTransitionFade
+)
+
+// Builds the widget for a route, given the navigator it is shown in and the parameters it was pushed with
+type RouteBuilder func(nav *NavigatorController, params map[string]string) Widget
+
+// A screen that can be pushed onto a Navigator
+type Route struct {
+	// Name of the route, which is set automatically for routes pushed by name
+	Name    string
+	Params  map[string]string
+	Builder RouteBuilder
+}
+
+// Shows the top of a stack of routes. Routes below the top are kept alive, so their state is preserved until they are popped.
+type Navigator struct {
+	Widget
+
+	// Builders for the routes that can be pushed by name
+	Routes       map[string]RouteBuilder
+	InitialRoute string
+	Transition   RouteTransition
+	// Length of transitions, defaulting to 200ms when zero
+	TransitionDuration time.Duration
+	// Keys that pop the top route, as either a single character or a key name such as "Esc", defaulting to Escape when nil
+	BackKeys []string
+}
+
+var _ StateWidget = Navigator{}
+
+const defaultRouteTransitionDuration = 200 * time.Millisecond
+
+// How often a Navigator rerenders while a transition is running
+const routeTransitionFrameInterval = 16 * time.Millisecond
+
+func (w Navigator) Build() (Widget, error) {
+	triggerRender := UseTriggerRender()
+	nav := UseRefFunc(func() *NavigatorController {
+		nav := &NavigatorController{}
+		nav.routes = w.Routes
+		nav.triggerRender = triggerRender
+		nav.push(nav.routeNamed(w.InitialRoute, nil))
+		return nav
+	})
+
+	duration := w.TransitionDuration
+	if duration == 0 {
+		duration = defaultRouteTransitionDuration
+	}
+
+	nav.lock.Lock()
+	nav.routes = w.Routes
+	nav.transition = w.Transition
+	view, routes, animating := nav.view(duration)
+	nav.lock.Unlock()
+
+	// Routes are built without holding the lock, since their builders are free to use the controller, such as to check CanPop
+	for i, route := range routes {
+		view.entries[i].child = route.Builder(nav, route.Params)
+	}
+
+	// Keep rendering every frame while a transition is running
+	frameInterval := time.Duration(0)
+	if animating {
+		frameInterval = routeTransitionFrameInterval
+	}
+	UseFrameClock(frameInterval)
+
+	UseEvent(func(context EventContext) {
+		event, ok := context.Event.(*tcell.EventKey)
+		if !ok || !nav.CanPop() {
+			return
+		}
+
+		if w.BackKeys == nil && event.Key() == tcell.KeyEscape {
+			nav.Pop(nil)
+			return
+		}
+		for _, backKey := range w.BackKeys {
+			if keyMatches(event, backKey) {
+				nav.Pop(nil)
+				return
+			}
+		}
+	})
+
+	return view, nil
+}
+
+type navigatorEntry struct {
+	id     int
+	route  Route
+	result chan any
+
+	transition RouteTransition
+	// When the entry started transitioning in, or out if it is being popped
+	transitionStart time.Time
+	popping         bool
+}
+
+// Changes the routes shown by a Navigator. It is passed to every RouteBuilder, and is safe to use from event listeners.
+type NavigatorController struct {
+	lock          sync.Mutex
+	routes        map[string]RouteBuilder
+	transition    RouteTransition
+	triggerRender func()
+
+	entries []*navigatorEntry
+	nextID  int
+}
+
+func (nav *NavigatorController) routeNamed(name string, params map[string]string) Route {
+	builder, ok := nav.routes[name]
+	if !ok {
+		builder = func(*NavigatorController, map[string]string) Widget {
+			return Center{Child: Text{Text: fmt.Sprintf("Route %q does not exist", name)}}
+		}
+	}
+
+	return Route{
+		Name:    name,
+		Params:  params,
+		Builder: builder,
+	}
+}
+
+func (nav *NavigatorController) push(route Route) <-chan any {
+	entry := &navigatorEntry{
+		id:              nav.nextID,
+		route:           route,
+		result:          make(chan any, 1),
+		transitionStart: time.Now(),
+	}
+	nav.nextID++
+
+	// The first route is shown immediately
+	if len(nav.entries) > 0 {
+		entry.transition = nav.transition
+	}
+
+	nav.entries = append(nav.entries, entry)
+
+	return entry.result
+}
+
+// Shows a route on top of the current one. The returned channel receives the result the route is popped with.
+func (nav *NavigatorController) Push(route Route) <-chan any {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+	defer nav.triggerRender()
+
+	return nav.push(route)
+}
+
+// Shows the route with the given name on top of the current one. The returned channel receives the result the route is popped with.
+func (nav *NavigatorController) PushNamed(name string, params map[string]string) <-chan any {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+	defer nav.triggerRender()
+
+	return nav.push(nav.routeNamed(name, params))
+}
+
+// Reports whether there is a route below the top one to go back to
+func (nav *NavigatorController) CanPop() bool {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+
+	return len(nav.activeEntries()) > 1
+}
+
+// Removes the top route, sending the result to whoever pushed it. The first route cannot be popped.
+func (nav *NavigatorController) Pop(result any) {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+	defer nav.triggerRender()
+
+	active := nav.activeEntries()
+	if len(active) <= 1 {
+		return
+	}
+
+	top := active[len(active)-1]
+	top.popping = true
+	top.transition = nav.transition
+	top.transitionStart = time.Now()
+	top.result <- result
+	close(top.result)
+}
+
+// Swaps the top route for a new one, discarding the state of the old route
+func (nav *NavigatorController) Replace(route Route) {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+	defer nav.triggerRender()
+
+	nav.replace(route)
+}
+
+// Swaps the top route for the route with the given name, discarding the state of the old route
+func (nav *NavigatorController) ReplaceNamed(name string, params map[string]string) {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+	defer nav.triggerRender()
+
+	nav.replace(nav.routeNamed(name, params))
+}
+
+func (nav *NavigatorController) replace(route Route) {
+	active := nav.activeEntries()
+	if len(active) > 0 {
+		top := active[len(active)-1]
+		close(top.result)
+		nav.entries = removeNavigatorEntry(nav.entries, top)
+	}
+
+	nav.push(route)
+}
+
+// Returns the route currently on top
+func (nav *NavigatorController) Current() Route {
+	nav.lock.Lock()
+	defer nav.lock.Unlock()
+
+	active := nav.activeEntries()
+	return active[len(active)-1].route
+}
+
+// Returns the entries that have not been popped
+func (nav *NavigatorController) activeEntries() []*navigatorEntry {
+	var active []*navigatorEntry
+	for _, entry := range nav.entries {
+		if !entry.popping {
+			active = append(active, entry)
+		}
+	}
+	return active
+}
+
+func removeNavigatorEntry(entries []*navigatorEntry, removed *navigatorEntry) []*navigatorEntry {
+	var result []*navigatorEntry
+	for _, entry := range entries {
+		if entry != removed {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Builds the view of the current entries, removing entries that have finished popping, and reports whether any transition is still running.
+// The children of the view are left for the caller to build from the returned routes, which line up with its entries.
+func (nav *NavigatorController) view(duration time.Duration) (navigatorView, []Route, bool) {
+	animating := false
+	view := navigatorView{}
+	routes := []Route{}
+
+	for _, entry := range nav.entries {
+		progress := 1.0
+		if entry.transition != RouteTransitionNone {
+			progress = min(float64(time.Since(entry.transitionStart))/float64(duration), 1)
+		}
+		if entry.popping {
+			progress = 1 - progress
+		}
+
+		if entry.popping && progress == 0 {
+			nav.entries = removeNavigatorEntry(nav.entries, entry)
+			continue
+		}
+		if progress < 1 {
+			animating = true
+		}
+
+		view.entries = append(view.entries, navigatorViewEntry{
+			id:         entry.id,
+			transition: entry.transition,
+			progress:   progress,
+		})
+		routes = append(routes, entry.route)
+	}
+
+	// Routes are hidden once they are fully covered by a route that is not transitioning
+	for i := len(view.entries) - 1; i > 0; i-- {
+		if view.entries[i].progress == 1 {
+			for j := 0; j < i; j++ {
+				view.entries[j].offstage = true
+			}
+			break
+		}
+	}
+
+	return view, routes, animating
+}
+
+type navigatorViewEntry struct {
+	id         int
+	child      Widget
+	transition RouteTransition
+	progress   float64
+	offstage   bool
+}
+
+type navigatorView struct {
+	Widget
+
+	entries []navigatorViewEntry
+}
+
+var _ RenderWidget = navigatorView{}
+
+func (w navigatorView) Layout(context LayoutContext) (Size, error) {
+	size := context.Constraints.Min
+
+	for _, entry := range w.entries {
+		opacity := 1.0
+		if entry.transition == RouteTransitionFade {
+			opacity = entry.progress
+		}
+
+		childSize, err := context.LayoutChild(entry.id, Opacity{
+			Child: entry.child,
+			Value: opacity,
+		}, context.Constraints)
+		if err != nil {
+			return Size{}, err
+		}
+
+		size.Width = DimensionInt(max(size.Width.Int(), childSize.Width.Int()))
+		size.Height = DimensionInt(max(size.Height.Int(), childSize.Height.Int()))
+	}
+
+	for _, entry := range w.entries {
+		pos := Pos{}
+		if entry.transition == RouteTransitionSlide {
+			pos.X = int(float64(size.Width.Int()) * (1 - entry.progress))
+		}
+
+		err := context.PositionChild(entry.id, pos)
+		if err != nil {
+			return Size{}, err
+		}
+		err = context.SetChildOffstage(entry.id, entry.offstage)
+		if err != nil {
+			return Size{}, err
+		}
+	}
+
+	return size, nil
+}
+
+func (w navigatorView) Paint(context PaintContext) error {
+	return nil
+}
@@ -6,21 +6,44 @@ import (
 	. "github.com/jwr1/goat"
 )
 
+// A box of a fixed size, which forces its child, if any, to be the same size
 type SizedBox struct {
 	Widget
 
+	Child  Widget
 	Width  int
 	Height int
+	// Takes up as much space as possible, ignoring Width and Height
+	Expand bool
 }
 
 var _ RenderWidget = SizedBox{}
 
 func (w SizedBox) Layout(context LayoutContext) (Size, error) {
-	return SizeInt(w.Width, w.Height), nil
+	size := SizeInt(w.Width, w.Height)
+	if w.Expand {
+		size = context.Constraints.Max
+		if size.HasInf() {
+			return Size{}, fmt.Errorf("SizedBox cannot expand to fill unbounded constraints")
+		}
+	}
+	size = size.Clamp(context.Constraints)
+
+	if w.Child != nil {
+		_, err := context.LayoutChild(0, w.Child, size.TightConstraints())
+		if err != nil {
+			return Size{}, err
+		}
+		err = context.PositionChild(0, Pos{})
+		if err != nil {
+			return Size{}, err
+		}
+	}
+
+	return size, nil
 }
 
 func (w SizedBox) Paint(context PaintContext) error {
-
 	return nil
 }
 
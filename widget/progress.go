@@ -0,0 +1,146 @@
+package goatw
+
+import (
+	"math"
+	"time"
+
+	. "github.com/jwr1/goat"
+)
+
+// Block characters filling the left eighths of a cell, indexed by the number of eighths filled
+var leftEighthBlocks = []string{" ", "▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"}
+
+// Shows how much of a task is done, as a bar that fills from left to right
+type ProgressBar struct {
+	Widget
+
+	// How much of the task is done, from 0 to 1
+	Value float64
+	// Shows a segment moving back and forth instead of the Value, for tasks where the progress is unknown
+	Indeterminate bool
+	// Text displayed over the middle of the bar, such as the percentage done
+	Label string
+	// Color of the filled part of the bar, defaulting to blue
+	Color Color
+	// Color of the unfilled part of the bar, defaulting to dark gray
+	TrackColor Color
+}
+
+var _ StateWidget = ProgressBar{}
+
+// How long the segment of an indeterminate ProgressBar takes to move across the bar and back
+const progressBarIndeterminatePeriod = 2 * time.Second
+
+// Fraction of an indeterminate ProgressBar covered by the segment
+const progressBarIndeterminateWidth = 0.25
+
+// How often an indeterminate ProgressBar rerenders
+const progressBarFrameInterval = 33 * time.Millisecond
+
+func (w ProgressBar) Build() (Widget, error) {
+	frameInterval := time.Duration(0)
+	if w.Indeterminate {
+		frameInterval = progressBarFrameInterval
+	}
+	now := UseFrameClock(frameInterval)
+
+	start, end := 0.0, min(max(w.Value, 0), 1)
+	if w.Indeterminate {
+		// Bounce the segment between the ends of the bar
+		phase := float64(now.UnixNano()%int64(progressBarIndeterminatePeriod)) / float64(progressBarIndeterminatePeriod)
+		start = (1 - math.Abs(phase*2-1)) * (1 - progressBarIndeterminateWidth)
+		end = start + progressBarIndeterminateWidth
+	}
+
+	return barView{
+		start:      start,
+		end:        end,
+		color:      defaultColor(w.Color, ColorRGB(0, 120, 215)),
+		trackColor: defaultColor(w.TrackColor, ColorRGB(50, 50, 50)),
+		label:      w.Label,
+	}, nil
+}
+
+// Returns the color, or the fallback if the color is fully transparent
+func defaultColor(color Color, fallback Color) Color {
+	if color.A == 0 {
+		return fallback
+	}
+	return color
+}
+
+// A single line bar with the part between start and end filled, as fractions of its width
+type barView struct {
+	Widget
+
+	start      float64
+	end        float64
+	color      Color
+	trackColor Color
+	label      string
+}
+
+var _ RenderWidget = barView{}
+
+// Width of a bar given unbounded constraints
+const defaultBarWidth = 20
+
+func (w barView) Layout(context LayoutContext) (Size, error) {
+	width := context.Constraints.Max.Width
+	if width.IsInf() {
+		width = DimensionInt(defaultBarWidth)
+	}
+
+	return Size{
+		Width:  width,
+		Height: DimensionInt(1),
+	}.Clamp(context.Constraints), nil
+}
+
+func (w barView) Paint(context PaintContext) error {
+	width := context.Size.Width.Int()
+	start := int(math.Round(w.start * float64(width*8)))
+	end := int(math.Round(w.end * float64(width*8)))
+
+	// Whether each cell is mostly filled, which decides the colors of the label drawn over it
+	filled := make([]bool, width)
+
+	for x := 0; x < width; x++ {
+		cellStart, cellEnd := x*8, x*8+8
+		covered := max(min(end, cellEnd)-max(start, cellStart), 0)
+		filled[x] = covered >= 4
+
+		cell := Cell{Grapheme: " ", Background: w.trackColor}
+		switch {
+		case covered == 8:
+			cell.Background = w.color
+		case covered == 0:
+		case start > cellStart && (end >= cellEnd || start-cellStart > cellEnd-end):
+			// Only the right part is filled, which is drawn as the unfilled left part in the track color over the filled color.
+			// Blocks can only be drawn from an edge of the cell, so a segment within the cell is drawn from whichever edge it is nearest to.
+			cell = Cell{Grapheme: leftEighthBlocks[8-covered], Foreground: w.trackColor, Background: w.color}
+		default:
+			cell = Cell{Grapheme: leftEighthBlocks[covered], Foreground: w.color, Background: w.trackColor}
+		}
+
+		context.Canvas.SetCell(x, 0, cell)
+	}
+
+	label := splitGraphemes(w.label)
+	if lineWidth(label) > width {
+		label = ellipsize(label, width)
+	}
+
+	x := (width - lineWidth(label)) / 2
+	for _, g := range label {
+		cell := Cell{Grapheme: g.paintText(), Width: g.width, Background: w.trackColor}
+		if filled[x] {
+			cell.Background = w.color
+		}
+
+		context.Canvas.SetCell(x, 0, cell)
+		x += g.width
+	}
+
+	return nil
+}
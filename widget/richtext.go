@@ -0,0 +1,234 @@
+package goatw
+
+import (
+	. "github.com/jwr1/goat"
+)
+
+// The style of a TextSpan. Unset fields are inherited from the parent span, where a fully transparent color counts as unset.
+type TextStyle struct {
+	Foreground Color
+	Background Color
+
+	Bold, Blink, Dim, Italic, Underline, StrikeThrough, Reverse bool
+
+	// Turns the text into a hyperlink to this URL (using OSC 8) on supporting terminals
+	Url string
+}
+
+// Returns the style of a child span with the given style, with any unset fields inherited from this style
+func (parent TextStyle) Merge(child TextStyle) TextStyle {
+	result := TextStyle{
+		Foreground:    parent.Foreground,
+		Background:    parent.Background,
+		Bold:          parent.Bold || child.Bold,
+		Blink:         parent.Blink || child.Blink,
+		Dim:           parent.Dim || child.Dim,
+		Italic:        parent.Italic || child.Italic,
+		Underline:     parent.Underline || child.Underline,
+		StrikeThrough: parent.StrikeThrough || child.StrikeThrough,
+		Reverse:       parent.Reverse || child.Reverse,
+		Url:           parent.Url,
+	}
+
+	if child.Foreground.A != 0 {
+		result.Foreground = child.Foreground
+	}
+	if child.Background.A != 0 {
+		result.Background = child.Background
+	}
+	if child.Url != "" {
+		result.Url = child.Url
+	}
+
+	return result
+}
+
+func (s TextStyle) cell(grapheme string, width int) Cell {
+	cell := Cell{
+		Grapheme:   grapheme,
+		Width:      width,
+		Foreground: s.Foreground,
+		Background: s.Background,
+	}
+
+	textStyle := CellTextStyle{
+		Bold:          s.Bold,
+		Blink:         s.Blink,
+		Dim:           s.Dim,
+		Italic:        s.Italic,
+		Underline:     s.Underline,
+		StrikeThrough: s.StrikeThrough,
+		Reverse:       s.Reverse,
+		Url:           s.Url,
+	}
+	if textStyle != (CellTextStyle{}) {
+		cell.TextStyle = &textStyle
+	}
+
+	return cell
+}
+
+// A piece of text with a style, followed by any number of child spans that inherit that style
+type TextSpan struct {
+	Text     string
+	Style    TextStyle
+	Children []TextSpan
+}
+
+func (s TextSpan) graphemes(parentStyle TextStyle) []textGrapheme {
+	style := parentStyle.Merge(s.Style)
+
+	result := splitGraphemes(s.Text)
+	for i := range result {
+		result[i].style = &style
+	}
+
+	for _, child := range s.Children {
+		result = append(result, child.graphemes(style)...)
+	}
+
+	return result
+}
+
+// Displays a tree of text spans, each with their own style, that are wrapped together like a single string
+type RichText struct {
+	Widget
+
+	Text      TextSpan
+	TextAlign TextAlign
+	// The maximum number of lines to display, where zero means no limit
+	MaxLines int
+	Overflow TextOverflow
+	// Only breaks lines at newlines, letting the text overflow horizontally instead of wrapping
+	DisableSoftWrap bool
+}
+
+var _ RenderWidget = RichText{}
+
+// The number of columns faded out by TextOverflowFade
+const textFadeWidth = 3
+
+func (w RichText) Layout(context LayoutContext) (Size, error) {
+	maxLineWidth := 0
+	lines, _ := w.lines(context.Constraints.Max.Width.Int(), context.Constraints.Max.Height.Int())
+
+	for _, line := range lines {
+		maxLineWidth = max(maxLineWidth, lineWidth(line.graphemes))
+	}
+
+	return SizeInt(
+		min(max(maxLineWidth, context.Constraints.Min.Width.Int()), context.Constraints.Max.Width.Int()),
+		min(max(len(lines), 1, context.Constraints.Min.Height.Int()), context.Constraints.Max.Height.Int()),
+	), nil
+}
+
+func (w RichText) Paint(context PaintContext) error {
+	width := context.Size.Width.Int()
+	lines, truncated := w.lines(width, context.Size.Height.Int())
+
+	for y, line := range lines {
+		graphemes := line.graphemes
+		overflowing := lineWidth(graphemes) > width || (truncated && y == len(lines)-1)
+
+		if overflowing && w.Overflow == TextOverflowEllipsis {
+			graphemes = ellipsize(graphemes, width)
+		}
+
+		x := 0
+		remainingSpace := width - lineWidth(graphemes)
+		switch w.TextAlign {
+		case TextAlignCenter:
+			x = max(remainingSpace/2, 0)
+		case TextAlignRight:
+			x = max(remainingSpace, 0)
+		}
+
+		// Spread the remaining space over the spaces of the line, giving earlier spaces any leftover columns
+		spaceCount, extraSpace := 0, 0
+		if w.TextAlign == TextAlignJustify && !line.endsParagraph && remainingSpace > 0 {
+			for _, g := range graphemes {
+				if g.isSpace() {
+					spaceCount++
+				}
+			}
+		}
+		if spaceCount > 0 {
+			extraSpace = remainingSpace
+		}
+
+		for _, g := range graphemes {
+			cell := g.style.cell(g.paintText(), g.width)
+
+			if overflowing && w.Overflow == TextOverflowFade && x >= width-textFadeWidth {
+				fadeCell(&cell, float64(width-x)/float64(textFadeWidth+1))
+			}
+
+			context.Canvas.SetCell(x, y, cell)
+			x += g.width
+
+			if g.isSpace() && spaceCount > 0 {
+				gap := (extraSpace + spaceCount - 1) / spaceCount
+				x += gap
+				extraSpace -= gap
+				spaceCount--
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the lines to display when given maxWidth columns and maxHeight rows, and whether any lines were dropped due to MaxLines or maxHeight
+func (w RichText) lines(maxWidth, maxHeight int) ([]textLine, bool) {
+	if w.DisableSoftWrap {
+		maxWidth = DimensionInfinite.Int()
+	}
+
+	lines := wordWrap(w.Text.graphemes(TextStyle{}), maxWidth)
+
+	maxLines := maxHeight
+	if w.MaxLines > 0 {
+		maxLines = min(w.MaxLines, maxHeight)
+	}
+	if len(lines) > maxLines {
+		return lines[:max(maxLines, 0)], true
+	}
+
+	return lines, false
+}
+
+// Shortens the line to fit within maxWidth columns, including a trailing "…"
+func ellipsize(line []textGrapheme, maxWidth int) []textGrapheme {
+	if maxWidth <= 0 {
+		return nil
+	}
+
+	style := &TextStyle{}
+	if len(line) > 0 {
+		style = line[len(line)-1].style
+	}
+
+	for len(line) > 0 && (lineWidth(line) > maxWidth-1 || line[len(line)-1].isSpace()) {
+		line = line[:len(line)-1]
+	}
+
+	result := make([]textGrapheme, len(line), len(line)+1)
+	copy(result, line)
+
+	return append(result, textGrapheme{text: "…", width: 1, style: style})
+}
+
+// Scales the foreground opacity of the cell by the factor. If the cell uses the default foreground, it is dimmed instead.
+func fadeCell(cell *Cell, factor float64) {
+	if cell.Foreground.A == 0 {
+		textStyle := CellTextStyle{}
+		if cell.TextStyle != nil {
+			textStyle = *cell.TextStyle
+		}
+		textStyle.Dim = true
+		cell.TextStyle = &textStyle
+		return
+	}
+
+	cell.Foreground.A = uint8(float64(cell.Foreground.A) * factor)
+}
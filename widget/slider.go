@@ -0,0 +1,159 @@
+package goatw
+
+import (
+	"math"
+	"sync"
+
+	. "github.com/jwr1/goat"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A bar for picking a number between Min and Max, by dragging it with the mouse, or with the arrow keys while focused.
+// The Value is controlled by the parent, which should update it when OnChange is called.
+type Slider struct {
+	Widget
+
+	Value    float64
+	Min      float64
+	Max      float64
+	OnChange func(value float64)
+	// Values are rounded to a multiple of Step from Min. If zero, any value can be picked, and the arrow keys move by a hundredth of the range.
+	Step float64
+	// Color of the part of the bar up to the value, defaulting to blue, or a brighter blue while focused
+	Color Color
+	// Color of the rest of the bar, defaulting to dark gray
+	TrackColor Color
+	Autofocus  bool
+}
+
+var _ StateWidget = Slider{}
+
+// Number of key presses it takes for Page Up and Page Down to move the same distance as the arrow keys
+const sliderPageSteps = 10
+
+func (w Slider) Build() (Widget, error) {
+	focused, requestFocus := UseFocus(w.Autofocus)
+	triggerRender := UseTriggerRender()
+	state := UseRefFunc(func() *sliderState {
+		return &sliderState{}
+	})
+
+	state.lock.Lock()
+	// Take the value from props whenever it differs, such as when the parent rejects a change, unless a change is yet to be passed to OnChange
+	if !state.reporting {
+		state.value = w.Value
+	}
+	state.lock.Unlock()
+
+	keyStep := w.Step
+	if keyStep <= 0 {
+		keyStep = (w.Max - w.Min) / 100
+	}
+
+	UseEvent(func(context EventContext) {
+		state.lock.Lock()
+		state.reporting = true
+		oldValue := state.value
+		state.handleEvent(context, w, keyStep, requestFocus)
+		newValue := state.value
+		state.lock.Unlock()
+
+		changed := newValue != oldValue
+		if changed && w.OnChange != nil {
+			w.OnChange(newValue)
+		}
+
+		state.lock.Lock()
+		state.reporting = false
+		state.lock.Unlock()
+
+		// Rebuild even if the parent ignores the change, so the slider goes back to its Value
+		if changed {
+			triggerRender()
+		}
+	})
+
+	fraction := 0.0
+	if w.Max > w.Min {
+		fraction = (w.clamp(w.Value) - w.Min) / (w.Max - w.Min)
+	}
+
+	color := defaultColor(w.Color, ColorRGB(0, 120, 215))
+	if focused && w.Color.A == 0 {
+		color = ColorRGB(0, 160, 255)
+	}
+
+	return barView{
+		end:        fraction,
+		color:      color,
+		trackColor: defaultColor(w.TrackColor, ColorRGB(50, 50, 50)),
+	}, nil
+}
+
+// The state of a Slider, shared between its event listener and builds
+type sliderState struct {
+	lock sync.Mutex
+
+	// The value the slider was last changed to, which key presses move from, so that presses made before the parent rebuilds are not lost
+	value float64
+	// Set while a change is yet to be passed to OnChange, so that Build does not revert it to the old value
+	reporting bool
+	dragging  bool
+}
+
+// Applies the event to the value
+func (s *sliderState) handleEvent(context EventContext, w Slider, keyStep float64, requestFocus func()) {
+	switch event := context.Event.(type) {
+	case *tcell.EventKey:
+		if !context.Focused {
+			return
+		}
+
+		switch event.Key() {
+		case tcell.KeyLeft, tcell.KeyDown:
+			s.value = w.clamp(s.value - keyStep)
+		case tcell.KeyRight, tcell.KeyUp:
+			s.value = w.clamp(s.value + keyStep)
+		case tcell.KeyPgDn:
+			s.value = w.clamp(s.value - keyStep*sliderPageSteps)
+		case tcell.KeyPgUp:
+			s.value = w.clamp(s.value + keyStep*sliderPageSteps)
+		case tcell.KeyHome:
+			s.value = w.clamp(w.Min)
+		case tcell.KeyEnd:
+			s.value = w.clamp(w.Max)
+		}
+
+	case *tcell.EventMouse:
+		x, y := event.Position()
+		pressed := event.Buttons()&tcell.ButtonPrimary != 0
+
+		if pressed && context.Contains(x, y) {
+			requestFocus()
+			s.dragging = true
+		}
+		if !pressed {
+			s.dragging = false
+		}
+		if !s.dragging {
+			return
+		}
+
+		// The first and last columns pick the ends of the range
+		width := context.RenderSize.Width.Int()
+		fraction := 1.0
+		if width > 1 {
+			fraction = float64(x-context.RenderPos.X) / float64(width-1)
+		}
+		s.value = w.clamp(w.Min + fraction*(w.Max-w.Min))
+	}
+}
+
+// Snaps the value to the nearest step, and keeps it between Min and Max
+func (w Slider) clamp(value float64) float64 {
+	if w.Step > 0 {
+		value = w.Min + math.Round((value-w.Min)/w.Step)*w.Step
+	}
+	return min(max(value, w.Min), w.Max)
+}
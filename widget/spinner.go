@@ -0,0 +1,58 @@
+package goatw
+
+import (
+	"time"
+
+	. "github.com/jwr1/goat"
+)
+
+// The frames of a Spinner's animation, and how long each is shown for
+type SpinnerFrames struct {
+	Frames   []string
+	Interval time.Duration
+}
+
+var (
+	SpinnerFramesDots    = SpinnerFrames{[]string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}, 80 * time.Millisecond}
+	SpinnerFramesLine    = SpinnerFrames{[]string{"-", "\\", "|", "/"}, 130 * time.Millisecond}
+	SpinnerFramesBraille = SpinnerFrames{[]string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}, 80 * time.Millisecond}
+	SpinnerFramesBounce  = SpinnerFrames{[]string{"⠁", "⠂", "⠄", "⠂"}, 120 * time.Millisecond}
+)
+
+// An animated indicator that something is loading, optionally followed by a label.
+// Spinners with the same frames are shown in sync, and stop animating while offstage or outside of the screen.
+type Spinner struct {
+	Widget
+
+	// Defaults to SpinnerFramesDots
+	Frames SpinnerFrames
+	Label  string
+	Color  Color
+}
+
+var _ StateWidget = Spinner{}
+
+func (w Spinner) Build() (Widget, error) {
+	frames := w.Frames
+	if len(frames.Frames) == 0 {
+		frames = SpinnerFramesDots
+	}
+
+	now := UseFrameClock(frames.Interval)
+
+	frame := 0
+	if frames.Interval > 0 {
+		frame = int(now.UnixNano() / int64(frames.Interval) % int64(len(frames.Frames)))
+	}
+
+	text := TextSpan{
+		Children: []TextSpan{
+			{Text: frames.Frames[frame], Style: TextStyle{Foreground: w.Color}},
+		},
+	}
+	if w.Label != "" {
+		text.Children = append(text.Children, TextSpan{Text: " " + w.Label})
+	}
+
+	return RichText{Text: text, DisableSoftWrap: true}, nil
+}
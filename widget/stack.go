@@ -0,0 +1,165 @@
+package goatw
+
+import (
+	"fmt"
+
+	. "github.com/jwr1/goat"
+)
+
+// How the children of a Stack that are not Positioned are constrained
+type StackFit int
+
+const (
+	// Children may be any size up to the Stack's maximum size
+	StackFitLoose StackFit = iota
+	// Children are forced to fill the Stack's maximum size
+	StackFitExpand
+	// Children are given the same constraints as the Stack
+	StackFitPassthrough
+)
+
+// Layers children on top of each other, with later children painted over earlier ones.
+//
+// The Stack sizes itself to fit its children that are not Positioned, which are then placed according to Alignment.
+// Positioned children are placed relative to the edges of the Stack afterwards.
+type Stack struct {
+	Widget
+
+	Children  []Widget
+	Alignment Alignment
+	Fit       StackFit
+}
+
+var _ RenderWidget = Stack{}
+
+func (w Stack) Layout(context LayoutContext) (Size, error) {
+	var childConstraints Constraints
+	switch w.Fit {
+	case StackFitLoose:
+		childConstraints = context.Constraints.Max.LooseConstraints()
+	case StackFitExpand:
+		if context.Constraints.Max.HasInf() {
+			return Size{}, fmt.Errorf("stack cannot expand its children within infinite constraints")
+		}
+		childConstraints = context.Constraints.Max.TightConstraints()
+	case StackFitPassthrough:
+		childConstraints = context.Constraints
+	}
+
+	childrenSizes := make([]Size, len(w.Children))
+	hasNonPositioned := false
+	size := context.Constraints.Min
+
+	for i, child := range w.Children {
+		if _, ok := child.(Positioned); ok {
+			continue
+		}
+
+		childSize, err := context.LayoutChild(i, child, childConstraints)
+		if err != nil {
+			return Size{}, err
+		}
+
+		childrenSizes[i] = childSize
+		hasNonPositioned = true
+		size.Width = DimensionInt(max(size.Width.Int(), childSize.Width.Int()))
+		size.Height = DimensionInt(max(size.Height.Int(), childSize.Height.Int()))
+	}
+
+	// Without any children to size itself by, fill as much space as possible
+	if !hasNonPositioned && !context.Constraints.Max.HasInf() {
+		size = context.Constraints.Max
+	}
+	size = size.Clamp(context.Constraints)
+
+	for i, child := range w.Children {
+		var err error
+		if positioned, ok := child.(Positioned); ok {
+			err = w.layoutPositioned(context, i, positioned, size)
+		} else {
+			err = context.PositionChild(i, w.Alignment.Offset(size, childrenSizes[i]))
+		}
+		if err != nil {
+			return Size{}, err
+		}
+	}
+
+	return size, nil
+}
+
+func (w Stack) layoutPositioned(context LayoutContext, key int, child Positioned, stackSize Size) error {
+	// Resolves the size of the child along one axis, which is fixed if set explicitly or if both edges are set
+	axisConstraints := func(start, end, size *int, stackSize int) (int, int) {
+		switch {
+		case size != nil:
+			return *size, *size
+		case start != nil && end != nil:
+			size := max(stackSize-*start-*end, 0)
+			return size, size
+		default:
+			return 0, stackSize
+		}
+	}
+
+	minWidth, maxWidth := axisConstraints(child.Left, child.Right, child.Width, stackSize.Width.Int())
+	minHeight, maxHeight := axisConstraints(child.Top, child.Bottom, child.Height, stackSize.Height.Int())
+
+	childSize, err := context.LayoutChild(key, child, Constraints{
+		Min: SizeInt(minWidth, minHeight),
+		Max: SizeInt(maxWidth, maxHeight),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Children without a set edge are placed according to the Stack's alignment
+	pos := w.Alignment.Offset(stackSize, childSize)
+	if child.Left != nil {
+		pos.X = *child.Left
+	} else if child.Right != nil {
+		pos.X = stackSize.Width.Int() - *child.Right - childSize.Width.Int()
+	}
+	if child.Top != nil {
+		pos.Y = *child.Top
+	} else if child.Bottom != nil {
+		pos.Y = stackSize.Height.Int() - *child.Bottom - childSize.Height.Int()
+	}
+
+	return context.PositionChild(key, pos)
+}
+
+func (w Stack) Paint(context PaintContext) error {
+	return nil
+}
+
+// Places its child within a Stack, at a distance from the Stack's edges.
+// Any field left as nil is not used. Setting both opposite edges also fixes the child's size along that axis.
+type Positioned struct {
+	Widget
+
+	Child  Widget
+	Left   *int
+	Top    *int
+	Right  *int
+	Bottom *int
+	Width  *int
+	Height *int
+}
+
+var _ RenderWidget = Positioned{}
+
+func (w Positioned) Layout(context LayoutContext) (Size, error) {
+	size, err := context.LayoutChild(0, w.Child, context.Constraints)
+	if err != nil {
+		return Size{}, err
+	}
+	err = context.PositionChild(0, Pos{})
+	if err != nil {
+		return Size{}, err
+	}
+	return size, nil
+}
+
+func (w Positioned) Paint(context PaintContext) error {
+	return nil
+}
@@ -3,114 +3,219 @@ package goatw
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
 
 	. "github.com/jwr1/goat"
 )
 
+type TextAlign int
+
+const (
+	TextAlignLeft TextAlign = iota
+	TextAlignCenter
+	TextAlignRight
+	// Stretches the spaces of each wrapped line so that it fills the whole width, except for the last line of each paragraph
+	TextAlignJustify
+)
+
+// How text that does not fit is displayed, either because a line is too wide or because there are more lines than MaxLines or the available height
+type TextOverflow int
+
+const (
+	TextOverflowClip TextOverflow = iota
+	// Replaces the end of the overflowing line with "…"
+	TextOverflowEllipsis
+	// Fades out the last few columns of the overflowing line
+	TextOverflowFade
+)
+
 type Text struct {
 	Widget
 
-	Text string
+	Text      string
+	TextAlign TextAlign
+	// The maximum number of lines to display, where zero means no limit
+	MaxLines int
+	Overflow TextOverflow
+	// Only breaks lines at newlines, letting the text overflow horizontally instead of wrapping
+	DisableSoftWrap bool
 }
 
 var _ RenderWidget = Text{}
 
 func (w Text) Layout(context LayoutContext) (Size, error) {
-	x, y, maxLineWidth := 0, 0, 0
+	return w.richText().Layout(context)
+}
 
-	for _, r := range wordWrap(w.Text, context.Constraints.Max.Width.Int()) {
-		switch r {
-		case '\n':
-			x = 0
-			y++
-		default:
-			x++
-			if x > maxLineWidth {
-				maxLineWidth = x
-			}
-		}
+func (w Text) Paint(context PaintContext) error {
+	return w.richText().Paint(context)
+}
+
+func (w Text) richText() RichText {
+	return RichText{
+		Text:            TextSpan{Text: w.Text},
+		TextAlign:       w.TextAlign,
+		MaxLines:        w.MaxLines,
+		Overflow:        w.Overflow,
+		DisableSoftWrap: w.DisableSoftWrap,
 	}
+}
 
-	return SizeInt(
-		max(maxLineWidth, context.Constraints.Min.Width.Int()),
-		max(y+1, context.Constraints.Min.Height.Int()),
-	), nil
+// A single user-perceived character, the number of columns it takes up, and the style of the span it came from
+type textGrapheme struct {
+	text  string
+	width int
+	style *TextStyle
+	// Position of the grapheme in the string it was split from
+	index int
 }
 
-func (w Text) Paint(context PaintContext) error {
-	x, y := 0, 0
-	for _, r := range wordWrap(w.Text, context.Size.Width.Int()) {
-		switch r {
-		case '\n':
-			x = 0
-			y++
-		default:
-			context.Canvas.SetCell(x, y, Cell{Rune: r})
-			x++
-		}
+func (g textGrapheme) isNewline() bool {
+	return g.text == "\n" || g.text == "\r\n"
+}
+
+// Reports whether the grapheme is whitespace that lines can be broken at, which leaves out newlines and non-breaking spaces
+func (g textGrapheme) isSpace() bool {
+	r, size := utf8.DecodeRuneInString(g.text)
+	if size != len(g.text) || g.isNewline() {
+		return false
+	}
+
+	switch r {
+	case '\u00a0', '\u2007', '\u202f':
+		return false
 	}
+	return unicode.IsSpace(r)
+}
 
-	return nil
+// Returns the text to paint for the grapheme, where a tab is painted as a space, since it takes up a single column like one.
+// Painting a raw tab would move the terminal's cursor to the next tab stop, shifting the rest of the row.
+func (g textGrapheme) paintText() string {
+	if g.text == "\t" {
+		return " "
+	}
+	return g.text
 }
 
-func wordWrap(text string, maxWidth int) string {
-	var output strings.Builder
-	var line strings.Builder
-	var block strings.Builder
+func splitGraphemes(text string) []textGrapheme {
+	var result []textGrapheme
+
+	state := -1
+	for len(text) > 0 {
+		var cluster string
+		cluster, text, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		result = append(result, textGrapheme{
+			text:  cluster,
+			width: GraphemeWidth(cluster),
+			index: len(result),
+		})
+	}
+
+	return result
+}
+
+func graphemesString(graphemes []textGrapheme) string {
+	var builder strings.Builder
+	for _, g := range graphemes {
+		builder.WriteString(g.text)
+	}
+	return builder.String()
+}
+
+func lineWidth(line []textGrapheme) int {
+	width := 0
+	for _, g := range line {
+		width += g.width
+	}
+	return width
+}
+
+type textLine struct {
+	graphemes []textGrapheme
+	// Reports whether the line was ended by a newline or the end of the text, rather than being wrapped
+	endsParagraph bool
+}
+
+// Breaks text into lines no wider than maxWidth, preferring to break between words.
+// Words that are wider than maxWidth are broken wherever they run out of space, and a grapheme wider than maxWidth on its own is replaced with "…".
+func wordWrap(text []textGrapheme, maxWidth int) []textLine {
+	var output []textLine
+	var line []textGrapheme
+	var block []textGrapheme
 
 	curLineWidth := 0
 
-	nextLine := func() {
-		output.WriteString(strings.TrimRightFunc(line.String(), unicode.IsSpace))
-		output.WriteRune('\n')
-		line.Reset()
+	nextLine := func(endsParagraph bool) {
+		for len(line) > 0 && line[len(line)-1].isSpace() {
+			line = line[:len(line)-1]
+		}
+		output = append(output, textLine{graphemes: line, endsParagraph: endsParagraph})
+		line = nil
 		curLineWidth = 0
 	}
 
 	consumeBlock := func() {
-		if block.Len() == 0 {
+		if len(block) == 0 {
 			return
 		}
 
+		blockWidth := lineWidth(block)
+
 		// If the block doesn't fit on this line, but would on the next.
-		if maxWidth-curLineWidth < block.Len() && block.Len() < maxWidth {
-			nextLine()
+		if maxWidth-curLineWidth < blockWidth && blockWidth <= maxWidth {
+			nextLine(false)
 		}
 
-		for _, r := range block.String() {
-			if curLineWidth >= maxWidth {
-				nextLine()
+		for _, g := range block {
+			if g.width > maxWidth && maxWidth > 0 {
+				g.text, g.width = "…", 1
+			}
+			if curLineWidth > 0 && curLineWidth+g.width > maxWidth {
+				nextLine(false)
 			}
 
-			line.WriteRune(r)
-			curLineWidth++
+			line = append(line, g)
+			curLineWidth += g.width
 		}
 
-		block.Reset()
+		block = nil
 	}
 
-	for _, r := range text {
-		switch r {
-		case '\n':
+	for _, g := range text {
+		switch {
+		case g.isNewline():
 			consumeBlock()
-			nextLine()
-		case ' ':
+			nextLine(true)
+		case g.isSpace():
 			consumeBlock()
-			if curLineWidth >= maxWidth {
-				nextLine()
+			// A space that does not fit is where the line breaks, so it is left out, and the line counts as full so that whatever follows starts the next one
+			if curLineWidth+g.width > maxWidth {
+				if curLineWidth > 0 {
+					curLineWidth = maxWidth
+				}
 			} else {
-				line.WriteRune(' ')
-				curLineWidth++
+				line = append(line, g)
+				curLineWidth += g.width
 			}
 		default:
-			block.WriteRune(r)
+			block = append(block, g)
 		}
 	}
 
 	consumeBlock()
-	if line.Len() > 0 {
-		nextLine()
+	if len(line) > 0 {
+		nextLine(true)
+	}
+
+	// Trailing blank lines take up no space
+	for len(output) > 0 && len(output[len(output)-1].graphemes) == 0 {
+		output = output[:len(output)-1]
+	}
+	if len(output) > 0 {
+		output[len(output)-1].endsParagraph = true
 	}
 
-	return strings.TrimRightFunc(output.String(), unicode.IsSpace)
+	return output
 }
@@ -0,0 +1,107 @@
+package goatw
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWordWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		want     []string
+	}{
+		{
+			name:     "fits on one line",
+			text:     "hello world",
+			maxWidth: 20,
+			want:     []string{"hello world"},
+		},
+		{
+			name:     "breaks between words",
+			text:     "hello big world",
+			maxWidth: 9,
+			want:     []string{"hello big", "world"},
+		},
+		{
+			name:     "space at the break is left out",
+			text:     "hello world",
+			maxWidth: 5,
+			want:     []string{"hello", "world"},
+		},
+		{
+			name:     "trailing spaces are trimmed",
+			text:     "ab   ",
+			maxWidth: 10,
+			want:     []string{"ab"},
+		},
+		{
+			name:     "word wider than the line is broken",
+			text:     "abcdefgh",
+			maxWidth: 3,
+			want:     []string{"abc", "def", "gh"},
+		},
+		{
+			name:     "newlines end lines",
+			text:     "a\n\nb\r\nc",
+			maxWidth: 10,
+			want:     []string{"a", "", "b", "c"},
+		},
+		{
+			name:     "wide graphemes are measured by columns",
+			text:     "世界世界",
+			maxWidth: 5,
+			want:     []string{"世界", "世界"},
+		},
+		{
+			name:     "wide grapheme does not fit in the remaining column",
+			text:     "a世",
+			maxWidth: 2,
+			want:     []string{"a", "世"},
+		},
+		{
+			name:     "wide emoji",
+			text:     "👍🏽 ok",
+			maxWidth: 3,
+			want:     []string{"👍🏽", "ok"},
+		},
+		{
+			name:     "wide grapheme wider than the line is replaced",
+			text:     "世a",
+			maxWidth: 1,
+			want:     []string{"…", "a"},
+		},
+		{
+			name:     "tabs are breakable",
+			text:     "ab\tcd",
+			maxWidth: 3,
+			want:     []string{"ab", "cd"},
+		},
+		{
+			name:     "non-breaking spaces are not breakable",
+			text:     "a\u00a0b c",
+			maxWidth: 3,
+			want:     []string{"a\u00a0b", "c"},
+		},
+		{
+			name:     "empty text",
+			text:     "",
+			maxWidth: 10,
+			want:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, line := range wordWrap(splitGraphemes(test.text), test.maxWidth) {
+				got = append(got, graphemesString(line.graphemes))
+			}
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
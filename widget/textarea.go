@@ -0,0 +1,729 @@
+package goatw
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/jwr1/goat"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/uniseg"
+)
+
+// The maximum number of edits that can be undone
+const textAreaHistoryLimit = 100
+
+// A multi-line text editor, which wraps lines the same way as Text. The Value is controlled by the parent, which should update it when OnChange is called.
+//
+// Besides the usual movement keys, it supports Ctrl+Z to undo, Ctrl+Y to redo, Ctrl+A to select all, and Ctrl+X, Ctrl+Insert and Ctrl+V to cut, copy and paste.
+// Ctrl+C is not supported for copying, since it always quits the app.
+type TextArea struct {
+	Widget
+
+	Value       string
+	OnChange    func(value string)
+	Placeholder string
+	// Shows the number of each line to the left of the text
+	LineNumbers bool
+	Autofocus   bool
+	// Called when cutting, copying or pasting fails, such as when no clipboard is available
+	OnClipboardError func(err error)
+}
+
+var _ StateWidget = TextArea{}
+
+func (w TextArea) Build() (Widget, error) {
+	focused, requestFocus := UseFocus(w.Autofocus)
+	triggerRender := UseTriggerRender()
+	state := UseRefFunc(func() *textAreaState {
+		state := &textAreaState{goalColumn: -1}
+		state.setValue("")
+		return state
+	})
+
+	state.lock.Lock()
+
+	// Take the value from props whenever it differs, such as when the parent rejects an edit, unless an edit is yet to be passed to OnChange
+	if !state.reporting && w.Value != state.String() {
+		state.setValue(w.Value)
+		state.cursor = state.clamp(state.cursor)
+		state.anchor = state.clamp(state.anchor)
+	}
+	state.lineNumbers = w.LineNumbers
+
+	view := textAreaView{
+		state:       state,
+		lines:       state.lines,
+		cursor:      state.cursor,
+		anchor:      state.anchor,
+		focused:     focused,
+		placeholder: w.Placeholder,
+		lineNumbers: w.LineNumbers,
+	}
+
+	state.lock.Unlock()
+
+	UseEvent(func(context EventContext) {
+		state.lock.Lock()
+		state.reporting = true
+		oldValue := state.String()
+		state.handleEvent(context, requestFocus)
+		request := state.clipboardRequest
+		state.clipboardRequest = clipboardRequest{}
+		state.lock.Unlock()
+
+		err := request.run(func(text string) {
+			state.lock.Lock()
+			state.paste(text)
+			state.lock.Unlock()
+		})
+		if err != nil && w.OnClipboardError != nil {
+			w.OnClipboardError(err)
+		}
+
+		state.lock.Lock()
+		newValue := state.String()
+		state.lock.Unlock()
+
+		if newValue != oldValue && w.OnChange != nil {
+			w.OnChange(newValue)
+		}
+
+		state.lock.Lock()
+		state.reporting = false
+		state.lock.Unlock()
+
+		triggerRender()
+	})
+
+	return view, nil
+}
+
+// A position in a TextArea, with the column counted in graphemes
+type textPos struct {
+	line   int
+	column int
+}
+
+func (p textPos) before(other textPos) bool {
+	return p.line < other.line || (p.line == other.line && p.column < other.column)
+}
+
+type textAreaEditKind int
+
+const (
+	textAreaEditOther textAreaEditKind = iota
+	textAreaEditTyping
+)
+
+type textAreaSnapshot struct {
+	value  string
+	cursor textPos
+	anchor textPos
+}
+
+// The editing state of a TextArea, shared between its event listener and its view
+type textAreaState struct {
+	lock sync.Mutex
+
+	lines       [][]textGrapheme
+	lineNumbers bool
+	// Set while an edit is yet to be passed to OnChange, so that Build does not revert it to the old value
+	reporting bool
+
+	cursor textPos
+	// The other end of the selection, which is empty when equal to cursor
+	anchor textPos
+	// The column that up and down movement tries to stay in, or -1 if it should be taken from the cursor
+	goalColumn int
+
+	// First visible row
+	scroll int
+	// Reports whether the next paint should scroll to the cursor
+	revealCursor bool
+	// Size of the text area excluding the line numbers, as of the last paint
+	width, height int
+
+	undoHistory  []textAreaSnapshot
+	redoHistory  []textAreaSnapshot
+	lastEditKind textAreaEditKind
+
+	dragging bool
+
+	clipboardRequest clipboardRequest
+}
+
+func (s *textAreaState) String() string {
+	var builder strings.Builder
+	for i, line := range s.lines {
+		if i > 0 {
+			builder.WriteRune('\n')
+		}
+		builder.WriteString(graphemesString(line))
+	}
+	return builder.String()
+}
+
+func (s *textAreaState) setValue(value string) {
+	s.lines = nil
+	for _, line := range strings.Split(value, "\n") {
+		s.lines = append(s.lines, splitGraphemes(line))
+	}
+}
+
+func (s *textAreaState) end() textPos {
+	return textPos{line: len(s.lines) - 1, column: len(s.lines[len(s.lines)-1])}
+}
+
+func (s *textAreaState) clamp(pos textPos) textPos {
+	pos.line = min(max(pos.line, 0), len(s.lines)-1)
+	pos.column = min(max(pos.column, 0), len(s.lines[pos.line]))
+	return pos
+}
+
+func (s *textAreaState) selection() (textPos, textPos) {
+	if s.anchor.before(s.cursor) {
+		return s.anchor, s.cursor
+	}
+	return s.cursor, s.anchor
+}
+
+func (s *textAreaState) hasSelection() bool {
+	return s.cursor != s.anchor
+}
+
+func (s *textAreaState) textBetween(start, end textPos) string {
+	if start.line == end.line {
+		return graphemesString(s.lines[start.line][start.column:end.column])
+	}
+
+	var builder strings.Builder
+	builder.WriteString(graphemesString(s.lines[start.line][start.column:]))
+	for line := start.line + 1; line < end.line; line++ {
+		builder.WriteRune('\n')
+		builder.WriteString(graphemesString(s.lines[line]))
+	}
+	builder.WriteRune('\n')
+	builder.WriteString(graphemesString(s.lines[end.line][:end.column]))
+	return builder.String()
+}
+
+// Moves the cursor, keeping the anchor in place if the selection is being extended
+func (s *textAreaState) setCursor(pos textPos, extendSelection bool) {
+	s.cursor = s.clamp(pos)
+	if !extendSelection {
+		s.anchor = s.cursor
+	}
+	s.goalColumn = -1
+	s.revealCursor = true
+	s.lastEditKind = textAreaEditOther
+}
+
+func (s *textAreaState) snapshot() textAreaSnapshot {
+	return textAreaSnapshot{
+		value:  s.String(),
+		cursor: s.cursor,
+		anchor: s.anchor,
+	}
+}
+
+func (s *textAreaState) restore(snapshot textAreaSnapshot) {
+	s.setValue(snapshot.value)
+	s.setCursor(snapshot.anchor, false)
+	s.setCursor(snapshot.cursor, true)
+}
+
+// Replaces the text between start and end, placing the cursor after the inserted text.
+// Consecutive typing is merged into a single undo step.
+func (s *textAreaState) replace(start, end textPos, text string, kind textAreaEditKind) {
+	if kind != textAreaEditTyping || s.lastEditKind != textAreaEditTyping {
+		s.undoHistory = append(s.undoHistory, s.snapshot())
+		if len(s.undoHistory) > textAreaHistoryLimit {
+			s.undoHistory = s.undoHistory[1:]
+		}
+	}
+	s.redoHistory = nil
+
+	before := s.textBetween(textPos{}, start) + text
+	after := s.textBetween(end, s.end())
+
+	// Resplit the whole value, since combining characters can join onto the graphemes around them
+	s.setValue(before + after)
+
+	beforeLines := strings.Split(before, "\n")
+	s.setCursor(textPos{
+		line:   len(beforeLines) - 1,
+		column: uniseg.GraphemeClusterCount(beforeLines[len(beforeLines)-1]),
+	}, false)
+
+	// Typing a space ends the current undo step, so words are undone one at a time
+	if kind == textAreaEditTyping && text != " " {
+		s.lastEditKind = textAreaEditTyping
+	}
+}
+
+func (s *textAreaState) insert(text string, kind textAreaEditKind) {
+	start, end := s.selection()
+	s.replace(start, end, text, kind)
+}
+
+// Inserts the pasted text
+func (s *textAreaState) paste(text string) {
+	s.insert(strings.ReplaceAll(text, "\r\n", "\n"), textAreaEditOther)
+}
+
+// Deletes the selection, or if there is none, the text between the cursor and pos
+func (s *textAreaState) deleteTo(pos textPos) {
+	start, end := s.selection()
+	if !s.hasSelection() {
+		pos = s.clamp(pos)
+		if pos.before(s.cursor) {
+			start, end = pos, s.cursor
+		} else {
+			start, end = s.cursor, pos
+		}
+	}
+	s.replace(start, end, "", textAreaEditOther)
+}
+
+func (s *textAreaState) undo() {
+	if len(s.undoHistory) == 0 {
+		return
+	}
+
+	s.redoHistory = append(s.redoHistory, s.snapshot())
+	s.restore(s.undoHistory[len(s.undoHistory)-1])
+	s.undoHistory = s.undoHistory[:len(s.undoHistory)-1]
+}
+
+func (s *textAreaState) redo() {
+	if len(s.redoHistory) == 0 {
+		return
+	}
+
+	s.undoHistory = append(s.undoHistory, s.snapshot())
+	s.restore(s.redoHistory[len(s.redoHistory)-1])
+	s.redoHistory = s.redoHistory[:len(s.redoHistory)-1]
+}
+
+// Returns the position one grapheme before pos, moving onto the previous line at the start of a line
+func (s *textAreaState) left(pos textPos) textPos {
+	if pos.column == 0 && pos.line > 0 {
+		return textPos{line: pos.line - 1, column: len(s.lines[pos.line-1])}
+	}
+	return s.clamp(textPos{line: pos.line, column: pos.column - 1})
+}
+
+// Returns the position one grapheme after pos, moving onto the next line at the end of a line
+func (s *textAreaState) right(pos textPos) textPos {
+	if pos.column == len(s.lines[pos.line]) && pos.line < len(s.lines)-1 {
+		return textPos{line: pos.line + 1, column: 0}
+	}
+	return s.clamp(textPos{line: pos.line, column: pos.column + 1})
+}
+
+// Returns the position of the start of the word before pos
+func (s *textAreaState) wordLeft(pos textPos) textPos {
+	if pos.column == 0 {
+		return s.left(pos)
+	}
+
+	line := s.lines[pos.line]
+	for pos.column > 0 && line[pos.column-1].isSpace() {
+		pos.column--
+	}
+	for pos.column > 0 && !line[pos.column-1].isSpace() {
+		pos.column--
+	}
+	return pos
+}
+
+// Returns the position of the end of the word after pos
+func (s *textAreaState) wordRight(pos textPos) textPos {
+	line := s.lines[pos.line]
+	if pos.column == len(line) {
+		return s.right(pos)
+	}
+
+	for pos.column < len(line) && line[pos.column].isSpace() {
+		pos.column++
+	}
+	for pos.column < len(line) && !line[pos.column].isSpace() {
+		pos.column++
+	}
+	return pos
+}
+
+// Returns the rows that a line is displayed on when wrapped to width, which is always at least one
+func (s *textAreaState) rows(line int) []textLine {
+	width := s.width
+	if width <= 0 {
+		width = DimensionInfinite.Int()
+	}
+
+	rows := wordWrap(s.lines[line], width)
+	if len(rows) == 0 {
+		rows = []textLine{{}}
+	}
+	return rows
+}
+
+// Returns the column of the line that a row starts at
+func rowStart(rows []textLine, row int) int {
+	for ; row >= 0; row-- {
+		if len(rows[row].graphemes) > 0 {
+			return rows[row].graphemes[0].index
+		}
+	}
+	return 0
+}
+
+// Returns the row of the whole text area that a position is displayed on, and the column within that row
+func (s *textAreaState) visualPos(pos textPos) (int, int) {
+	row := 0
+	for line := 0; line < pos.line; line++ {
+		row += len(s.rows(line))
+	}
+
+	rows := s.rows(pos.line)
+	r := len(rows) - 1
+	for r > 0 && rowStart(rows, r) > pos.column {
+		r--
+	}
+
+	x := lineWidth(s.lines[pos.line][rowStart(rows, r):pos.column])
+	if s.width > 0 {
+		// Spaces at the end of a wrapped row are not displayed, so the cursor stays at the edge instead
+		x = min(x, s.width-1)
+	}
+
+	return row + r, x
+}
+
+// Returns the position displayed at a row of the whole text area and a column within that row
+func (s *textAreaState) posAtVisual(row, x int) textPos {
+	if row < 0 {
+		return textPos{}
+	}
+
+	for line := range s.lines {
+		rows := s.rows(line)
+		if row >= len(rows) {
+			row -= len(rows)
+			continue
+		}
+
+		column := rowStart(rows, row)
+		end := len(s.lines[line])
+		if row < len(rows)-1 {
+			end = rowStart(rows, row+1)
+		}
+
+		for width := 0; column < end && x >= width+s.lines[line][column].width; column++ {
+			width += s.lines[line][column].width
+		}
+
+		// Stay on this row rather than moving onto the start of the next
+		if column == end && row < len(rows)-1 {
+			column = max(end-1, rowStart(rows, row))
+		}
+
+		return textPos{line: line, column: column}
+	}
+
+	return s.end()
+}
+
+// Moves the cursor up or down by a number of rows, staying as close as possible to the column it started in
+func (s *textAreaState) moveRows(delta int, extendSelection bool) {
+	row, x := s.visualPos(s.cursor)
+	if s.goalColumn >= 0 {
+		x = s.goalColumn
+	}
+
+	s.setCursor(s.posAtVisual(row+delta, x), extendSelection)
+	s.goalColumn = x
+}
+
+func (s *textAreaState) handleEvent(context EventContext, requestFocus func()) {
+	switch event := context.Event.(type) {
+	case *tcell.EventMouse:
+		x, y := event.Position()
+		pressed := event.Buttons()&tcell.ButtonPrimary != 0
+		pos := s.posAtVisual(y-context.RenderPos.Y+s.scroll, x-context.RenderPos.X-s.gutterWidth())
+
+		switch {
+		case event.Buttons()&tcell.WheelUp != 0 && context.Contains(x, y):
+			s.scroll = max(s.scroll-3, 0)
+		case event.Buttons()&tcell.WheelDown != 0 && context.Contains(x, y):
+			s.scroll += 3
+		case pressed && s.dragging:
+			s.setCursor(pos, true)
+		case pressed && context.Contains(x, y):
+			requestFocus()
+			s.setCursor(pos, event.Modifiers()&tcell.ModShift != 0)
+			s.dragging = true
+		case !pressed:
+			s.dragging = false
+		}
+
+	case *tcell.EventKey:
+		if !context.Focused {
+			return
+		}
+
+		shift := event.Modifiers()&tcell.ModShift != 0
+		ctrl := event.Modifiers()&tcell.ModCtrl != 0
+		wordWise := event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0
+		start, end := s.selection()
+
+		switch event.Key() {
+		case tcell.KeyRune:
+			s.insert(string(event.Rune()), textAreaEditTyping)
+		case tcell.KeyEnter:
+			s.insert("\n", textAreaEditOther)
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if wordWise {
+				s.deleteTo(s.wordLeft(s.cursor))
+			} else {
+				s.deleteTo(s.left(s.cursor))
+			}
+		case tcell.KeyDelete:
+			switch {
+			case shift:
+				if s.hasSelection() {
+					s.clipboardRequest = clipboardRequest{copy: true, text: s.textBetween(start, end)}
+					s.deleteTo(s.cursor)
+				}
+			case wordWise:
+				s.deleteTo(s.wordRight(s.cursor))
+			default:
+				s.deleteTo(s.right(s.cursor))
+			}
+		case tcell.KeyLeft:
+			switch {
+			case wordWise:
+				s.setCursor(s.wordLeft(s.cursor), shift)
+			case s.hasSelection() && !shift:
+				s.setCursor(start, false)
+			default:
+				s.setCursor(s.left(s.cursor), shift)
+			}
+		case tcell.KeyRight:
+			switch {
+			case wordWise:
+				s.setCursor(s.wordRight(s.cursor), shift)
+			case s.hasSelection() && !shift:
+				s.setCursor(end, false)
+			default:
+				s.setCursor(s.right(s.cursor), shift)
+			}
+		case tcell.KeyUp:
+			s.moveRows(-1, shift)
+		case tcell.KeyDown:
+			s.moveRows(1, shift)
+		case tcell.KeyPgUp:
+			s.moveRows(-max(s.height-1, 1), shift)
+		case tcell.KeyPgDn:
+			s.moveRows(max(s.height-1, 1), shift)
+		case tcell.KeyHome:
+			if ctrl {
+				s.setCursor(textPos{}, shift)
+			} else {
+				s.setCursor(textPos{line: s.cursor.line}, shift)
+			}
+		case tcell.KeyEnd:
+			if ctrl {
+				s.setCursor(s.end(), shift)
+			} else {
+				s.setCursor(textPos{line: s.cursor.line, column: len(s.lines[s.cursor.line])}, shift)
+			}
+		case tcell.KeyCtrlA:
+			s.setCursor(textPos{}, false)
+			s.setCursor(s.end(), true)
+		case tcell.KeyCtrlZ:
+			s.undo()
+		case tcell.KeyCtrlY:
+			s.redo()
+		case tcell.KeyCtrlX:
+			if s.hasSelection() {
+				s.clipboardRequest = clipboardRequest{copy: true, text: s.textBetween(start, end)}
+				s.deleteTo(s.cursor)
+			}
+		case tcell.KeyInsert:
+			if ctrl && s.hasSelection() {
+				s.clipboardRequest = clipboardRequest{copy: true, text: s.textBetween(start, end)}
+			} else if shift {
+				s.clipboardRequest = clipboardRequest{paste: true}
+			}
+		case tcell.KeyCtrlV:
+			s.clipboardRequest = clipboardRequest{paste: true}
+		}
+	}
+}
+
+func (s *textAreaState) gutterWidth() int {
+	if !s.lineNumbers {
+		return 0
+	}
+	return textAreaGutterWidth(len(s.lines))
+}
+
+// Returns the width of the line numbers, including a space to separate them from the text
+func textAreaGutterWidth(lineCount int) int {
+	return len(strconv.Itoa(lineCount)) + 1
+}
+
+type textAreaView struct {
+	Widget
+
+	state       *textAreaState
+	lines       [][]textGrapheme
+	cursor      textPos
+	anchor      textPos
+	focused     bool
+	placeholder string
+	lineNumbers bool
+}
+
+var _ RenderWidget = textAreaView{}
+
+func (w textAreaView) gutterWidth() int {
+	if !w.lineNumbers {
+		return 0
+	}
+	return textAreaGutterWidth(len(w.lines))
+}
+
+func (w textAreaView) Layout(context LayoutContext) (Size, error) {
+	size := context.Constraints.Max
+
+	if size.Width.IsInf() {
+		maxLineWidth := lineWidth(splitGraphemes(w.placeholder))
+		for _, line := range w.lines {
+			maxLineWidth = max(maxLineWidth, lineWidth(line))
+		}
+
+		// Leave room for the cursor after the last grapheme
+		size.Width = DimensionInt(w.gutterWidth() + maxLineWidth + 1)
+	}
+
+	if size.Height.IsInf() {
+		rows := 0
+		for _, line := range w.lines {
+			rows += max(len(wordWrap(line, size.Width.Int()-w.gutterWidth())), 1)
+		}
+		size.Height = DimensionInt(rows)
+	}
+
+	return size.Clamp(context.Constraints), nil
+}
+
+func (w textAreaView) Paint(context PaintContext) error {
+	gutterWidth := w.gutterWidth()
+	height := context.Size.Height.Int()
+
+	w.state.lock.Lock()
+
+	state := &textAreaState{
+		lines:       w.lines,
+		lineNumbers: w.lineNumbers,
+		width:       max(context.Size.Width.Int()-gutterWidth, 1),
+	}
+	w.state.width = state.width
+	w.state.height = height
+
+	totalRows := 0
+	for line := range state.lines {
+		totalRows += len(state.rows(line))
+	}
+
+	// Scroll just enough to keep the cursor visible, but only after it moves, so that scrolling with the mouse wheel is not undone
+	scroll := min(w.state.scroll, max(totalRows-height, 0))
+	if w.state.revealCursor {
+		cursorRow, _ := state.visualPos(w.cursor)
+		scroll = min(scroll, cursorRow)
+		scroll = max(scroll, cursorRow-height+1)
+		w.state.revealCursor = false
+	}
+	w.state.scroll = scroll
+
+	w.state.lock.Unlock()
+
+	selectionStart, selectionEnd := w.anchor, w.cursor
+	if selectionEnd.before(selectionStart) {
+		selectionStart, selectionEnd = selectionEnd, selectionStart
+	}
+
+	if len(w.lines) == 1 && len(w.lines[0]) == 0 {
+		for y, line := range wordWrap(splitGraphemes(w.placeholder), state.width) {
+			x := gutterWidth
+			for _, g := range line.graphemes {
+				context.Canvas.SetCell(x, y, Cell{
+					Grapheme:  g.paintText(),
+					Width:     g.width,
+					TextStyle: &CellTextStyle{Dim: true},
+				})
+				x += g.width
+			}
+		}
+	}
+
+	y := -scroll
+	for lineIndex := range w.lines {
+		for r, row := range state.rows(lineIndex) {
+			if y >= height {
+				break
+			}
+
+			if w.lineNumbers && r == 0 {
+				number := strconv.Itoa(lineIndex + 1)
+				for i, digit := range number {
+					context.Canvas.SetCell(gutterWidth-1-len(number)+i, y, Cell{
+						Grapheme:  string(digit),
+						Width:     1,
+						TextStyle: &CellTextStyle{Dim: true},
+					})
+				}
+			}
+
+			x := gutterWidth
+			for _, g := range row.graphemes {
+				cell := Cell{Grapheme: g.paintText(), Width: g.width}
+
+				pos := textPos{line: lineIndex, column: g.index}
+				if w.focused && !pos.before(selectionStart) && pos.before(selectionEnd) {
+					cell.TextStyle = &CellTextStyle{Reverse: true}
+				}
+
+				context.Canvas.SetCell(x, y, cell)
+				x += g.width
+			}
+
+			y++
+		}
+	}
+
+	if w.focused && w.anchor == w.cursor {
+		cursorRow, cursorX := state.visualPos(w.cursor)
+		x, y := gutterWidth+cursorX, cursorRow-scroll
+
+		cell := context.Canvas.GetCell(x, y)
+		if cell.Grapheme == "" {
+			cell.Grapheme = " "
+			cell.Width = 1
+		}
+
+		textStyle := CellTextStyle{}
+		if cell.TextStyle != nil {
+			textStyle = *cell.TextStyle
+		}
+		textStyle.Reverse = true
+		cell.TextStyle = &textStyle
+
+		context.Canvas.SetCell(x, y, cell)
+	}
+
+	return nil
+}
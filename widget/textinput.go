@@ -0,0 +1,433 @@
+package goatw
+
+import (
+	"strings"
+	"sync"
+
+	. "github.com/jwr1/goat"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/uniseg"
+)
+
+// A single line text field. The Value is controlled by the parent, which should update it when OnChange is called.
+//
+// Besides the usual movement keys, it supports Ctrl+A to select all, and Ctrl+X, Ctrl+Insert and Ctrl+V to cut, copy and paste.
+// Ctrl+C is not supported for copying, since it always quits the app.
+// Password inputs cannot be cut or copied from.
+type TextInput struct {
+	Widget
+
+	Value    string
+	OnChange func(value string)
+	// Called with the current value when Enter is pressed
+	OnSubmit    func(value string)
+	Placeholder string
+	// Displays every character as "•", for passwords and other secrets
+	Password  bool
+	Autofocus bool
+	// Called when cutting, copying or pasting fails, such as when no clipboard is available
+	OnClipboardError func(err error)
+}
+
+var _ StateWidget = TextInput{}
+
+func (w TextInput) Build() (Widget, error) {
+	focused, requestFocus := UseFocus(w.Autofocus)
+	triggerRender := UseTriggerRender()
+	state := UseRefFunc(func() *textInputState {
+		return &textInputState{}
+	})
+
+	state.lock.Lock()
+
+	// Take the value from props whenever it differs, such as when the parent rejects an edit, unless an edit is yet to be passed to OnChange
+	if !state.reporting && w.Value != state.String() {
+		state.value = splitGraphemes(w.Value)
+		state.cursor = min(state.cursor, len(state.value))
+		state.anchor = min(state.anchor, len(state.value))
+	}
+	state.password = w.Password
+
+	selectionStart, selectionEnd := state.selection()
+	view := textInputView{
+		state:          state,
+		value:          state.display(),
+		cursor:         state.cursor,
+		selectionStart: selectionStart,
+		selectionEnd:   selectionEnd,
+		focused:        focused,
+		placeholder:    w.Placeholder,
+	}
+
+	state.lock.Unlock()
+
+	UseEvent(func(context EventContext) {
+		state.lock.Lock()
+		state.reporting = true
+		oldValue := state.String()
+		submit := state.handleEvent(context, requestFocus)
+		request := state.clipboardRequest
+		state.clipboardRequest = clipboardRequest{}
+		state.lock.Unlock()
+
+		err := request.run(func(text string) {
+			state.lock.Lock()
+			state.paste(text)
+			state.lock.Unlock()
+		})
+		if err != nil && w.OnClipboardError != nil {
+			w.OnClipboardError(err)
+		}
+
+		state.lock.Lock()
+		newValue := state.String()
+		state.lock.Unlock()
+
+		if newValue != oldValue && w.OnChange != nil {
+			w.OnChange(newValue)
+		}
+		if submit && w.OnSubmit != nil {
+			w.OnSubmit(newValue)
+		}
+
+		state.lock.Lock()
+		state.reporting = false
+		state.lock.Unlock()
+
+		triggerRender()
+	})
+
+	return view, nil
+}
+
+// The editing state of a TextInput, shared between its event listener and its view
+type textInputState struct {
+	lock sync.Mutex
+
+	value    []textGrapheme
+	password bool
+	// Set while an edit is yet to be passed to OnChange, so that Build does not revert it to the old value
+	reporting bool
+
+	// Grapheme index of the cursor
+	cursor int
+	// Grapheme index of the other end of the selection, which is empty when equal to cursor
+	anchor int
+	// First column of the value that is visible
+	scroll int
+
+	dragging bool
+	pasting  bool
+
+	clipboardRequest clipboardRequest
+}
+
+// A clipboard operation asked for by a key press. It is run once the editing state is unlocked, since the clipboard may run external commands.
+type clipboardRequest struct {
+	copy  bool
+	text  string
+	paste bool
+}
+
+// Copies the text, or reads the clipboard and passes its contents to paste
+func (r clipboardRequest) run(paste func(text string)) error {
+	switch {
+	case r.copy:
+		return Clipboard.Write(r.text)
+	case r.paste:
+		text, err := Clipboard.Read()
+		if err != nil {
+			return err
+		}
+		paste(text)
+	}
+
+	return nil
+}
+
+func (s *textInputState) String() string {
+	return graphemesString(s.value)
+}
+
+// Returns the graphemes as they are displayed, which are masked for passwords
+func (s *textInputState) display() []textGrapheme {
+	if !s.password {
+		return s.value
+	}
+
+	masked := make([]textGrapheme, len(s.value))
+	for i := range masked {
+		masked[i] = textGrapheme{text: "•", width: 1}
+	}
+	return masked
+}
+
+func (s *textInputState) selection() (int, int) {
+	return min(s.cursor, s.anchor), max(s.cursor, s.anchor)
+}
+
+func (s *textInputState) hasSelection() bool {
+	return s.cursor != s.anchor
+}
+
+// Moves the cursor, keeping the anchor in place if the selection is being extended
+func (s *textInputState) setCursor(pos int, extendSelection bool) {
+	s.cursor = min(max(pos, 0), len(s.value))
+	if !extendSelection {
+		s.anchor = s.cursor
+	}
+}
+
+// Replaces the graphemes between start and end with text, placing the cursor after the inserted text
+func (s *textInputState) replace(start, end int, text string) {
+	before := graphemesString(s.value[:start]) + text
+	after := graphemesString(s.value[end:])
+
+	// Resplit the whole value, since combining characters can join onto the graphemes around them
+	s.value = splitGraphemes(before + after)
+	s.setCursor(uniseg.GraphemeClusterCount(before), false)
+}
+
+func (s *textInputState) insert(text string) {
+	start, end := s.selection()
+	s.replace(start, end, text)
+}
+
+// Inserts the pasted text, flattened onto a single line
+func (s *textInputState) paste(text string) {
+	s.insert(strings.NewReplacer("\r\n", " ", "\n", " ").Replace(text))
+}
+
+// Deletes the selection, or if there is none, the graphemes between the cursor and pos
+func (s *textInputState) deleteTo(pos int) {
+	start, end := s.selection()
+	if !s.hasSelection() {
+		pos = min(max(pos, 0), len(s.value))
+		start, end = min(s.cursor, pos), max(s.cursor, pos)
+	}
+	s.replace(start, end, "")
+}
+
+// Returns the index of the start of the word before pos
+func (s *textInputState) wordLeft(pos int) int {
+	for pos > 0 && s.value[pos-1].isSpace() {
+		pos--
+	}
+	for pos > 0 && !s.value[pos-1].isSpace() {
+		pos--
+	}
+	return pos
+}
+
+// Returns the index of the end of the word after pos
+func (s *textInputState) wordRight(pos int) int {
+	for pos < len(s.value) && s.value[pos].isSpace() {
+		pos++
+	}
+	for pos < len(s.value) && !s.value[pos].isSpace() {
+		pos++
+	}
+	return pos
+}
+
+// Returns the index of the grapheme displayed at the column, or the end of the value if there is none
+func (s *textInputState) indexAtColumn(column int) int {
+	x := 0
+	for i, g := range s.display() {
+		if column < x+g.width {
+			return i
+		}
+		x += g.width
+	}
+	return len(s.value)
+}
+
+// Applies the event to the state, reporting whether the value should be submitted
+func (s *textInputState) handleEvent(context EventContext, requestFocus func()) bool {
+	switch event := context.Event.(type) {
+	case *tcell.EventPaste:
+		s.pasting = event.Start()
+
+	case *tcell.EventMouse:
+		x, y := event.Position()
+		pressed := event.Buttons()&tcell.ButtonPrimary != 0
+		column := x - context.RenderPos.X + s.scroll
+
+		switch {
+		case pressed && s.dragging:
+			s.setCursor(s.indexAtColumn(column), true)
+		case pressed && context.Contains(x, y):
+			requestFocus()
+			s.setCursor(s.indexAtColumn(column), event.Modifiers()&tcell.ModShift != 0)
+			s.dragging = true
+		case !pressed:
+			s.dragging = false
+		}
+
+	case *tcell.EventKey:
+		if !context.Focused {
+			return false
+		}
+
+		shift := event.Modifiers()&tcell.ModShift != 0
+		ctrl := event.Modifiers()&tcell.ModCtrl != 0
+		wordWise := event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0
+		start, end := s.selection()
+
+		switch event.Key() {
+		case tcell.KeyRune:
+			s.insert(string(event.Rune()))
+		case tcell.KeyEnter:
+			// Pasted text can contain newlines, which are flattened onto this line
+			if s.pasting {
+				s.insert(" ")
+			} else {
+				return true
+			}
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if wordWise {
+				s.deleteTo(s.wordLeft(s.cursor))
+			} else {
+				s.deleteTo(s.cursor - 1)
+			}
+		case tcell.KeyDelete:
+			if shift {
+				// Secrets are never copied, so cutting them does nothing
+				if s.hasSelection() && !s.password {
+					s.clipboardRequest = clipboardRequest{copy: true, text: graphemesString(s.value[start:end])}
+					s.deleteTo(s.cursor)
+				}
+			} else if wordWise {
+				s.deleteTo(s.wordRight(s.cursor))
+			} else {
+				s.deleteTo(s.cursor + 1)
+			}
+		case tcell.KeyLeft:
+			switch {
+			case wordWise:
+				s.setCursor(s.wordLeft(s.cursor), shift)
+			case s.hasSelection() && !shift:
+				s.setCursor(start, false)
+			default:
+				s.setCursor(s.cursor-1, shift)
+			}
+		case tcell.KeyRight:
+			switch {
+			case wordWise:
+				s.setCursor(s.wordRight(s.cursor), shift)
+			case s.hasSelection() && !shift:
+				s.setCursor(end, false)
+			default:
+				s.setCursor(s.cursor+1, shift)
+			}
+		case tcell.KeyHome:
+			s.setCursor(0, shift)
+		case tcell.KeyEnd:
+			s.setCursor(len(s.value), shift)
+		case tcell.KeyCtrlA:
+			s.anchor = 0
+			s.setCursor(len(s.value), true)
+		case tcell.KeyCtrlX:
+			if s.hasSelection() && !s.password {
+				s.clipboardRequest = clipboardRequest{copy: true, text: graphemesString(s.value[start:end])}
+				s.deleteTo(s.cursor)
+			}
+		case tcell.KeyInsert:
+			if ctrl {
+				if s.hasSelection() && !s.password {
+					s.clipboardRequest = clipboardRequest{copy: true, text: graphemesString(s.value[start:end])}
+				}
+			} else if shift {
+				s.clipboardRequest = clipboardRequest{paste: true}
+			}
+		case tcell.KeyCtrlV:
+			s.clipboardRequest = clipboardRequest{paste: true}
+		}
+	}
+
+	return false
+}
+
+type textInputView struct {
+	Widget
+
+	state          *textInputState
+	value          []textGrapheme
+	cursor         int
+	selectionStart int
+	selectionEnd   int
+	focused        bool
+	placeholder    string
+}
+
+var _ RenderWidget = textInputView{}
+
+func (w textInputView) Layout(context LayoutContext) (Size, error) {
+	width := context.Constraints.Max.Width
+	if width.IsInf() {
+		// Leave room for the cursor after the last grapheme
+		width = DimensionInt(max(lineWidth(w.value), lineWidth(splitGraphemes(w.placeholder))) + 1)
+	}
+
+	return Size{
+		Width:  width,
+		Height: DimensionInt(1),
+	}.Clamp(context.Constraints), nil
+}
+
+func (w textInputView) Paint(context PaintContext) error {
+	width := context.Size.Width.Int()
+	cursorColumn := lineWidth(w.value[:w.cursor])
+
+	// Scroll just enough to keep the cursor visible
+	w.state.lock.Lock()
+	scroll := w.state.scroll
+	scroll = min(scroll, max(lineWidth(w.value)+1-width, 0))
+	scroll = min(scroll, cursorColumn)
+	scroll = max(scroll, cursorColumn-width+1)
+	w.state.scroll = scroll
+	w.state.lock.Unlock()
+
+	if len(w.value) == 0 {
+		x := 0
+		for _, g := range splitGraphemes(w.placeholder) {
+			context.Canvas.SetCell(x, 0, Cell{
+				Grapheme:  g.paintText(),
+				Width:     g.width,
+				TextStyle: &CellTextStyle{Dim: true},
+			})
+			x += g.width
+		}
+	}
+
+	x := -scroll
+	for i, g := range w.value {
+		cell := Cell{Grapheme: g.paintText(), Width: g.width}
+		if w.focused && w.selectionStart <= i && i < w.selectionEnd {
+			cell.TextStyle = &CellTextStyle{Reverse: true}
+		}
+
+		context.Canvas.SetCell(x, 0, cell)
+		x += g.width
+	}
+
+	if w.focused && w.selectionStart == w.selectionEnd {
+		cell := context.Canvas.GetCell(cursorColumn-scroll, 0)
+		if cell.Grapheme == "" {
+			cell.Grapheme = " "
+			cell.Width = 1
+		}
+
+		textStyle := CellTextStyle{}
+		if cell.TextStyle != nil {
+			textStyle = *cell.TextStyle
+		}
+		textStyle.Reverse = true
+		cell.TextStyle = &textStyle
+
+		context.Canvas.SetCell(cursorColumn-scroll, 0, cell)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package goatw
+
+import (
+	. "github.com/jwr1/goat"
+)
+
+// Builds the glyph of a toggle control followed by its label, highlighting the glyph when focused and underlining the label when hovered
+func toggleText(glyph string, glyphColor Color, label string, buttonState ButtonState, focused bool) Widget {
+	text := TextSpan{
+		Children: []TextSpan{
+			{Text: glyph, Style: TextStyle{Foreground: glyphColor, Reverse: focused}},
+		},
+	}
+	if label != "" {
+		text.Children = append(text.Children,
+			TextSpan{Text: " "},
+			TextSpan{Text: label, Style: TextStyle{Underline: buttonState != ButtonStateIdle}},
+		)
+	}
+
+	return RichText{Text: text}
+}
+
+type CheckboxValue int
+
+const (
+	CheckboxUnchecked CheckboxValue = iota
+	CheckboxChecked
+	// Neither checked nor unchecked, such as when only some of a group of options are checked
+	CheckboxIndeterminate
+)
+
+// The text displayed for each value of a Checkbox
+type CheckboxGlyphs struct {
+	Unchecked     string
+	Checked       string
+	Indeterminate string
+}
+
+var (
+	CheckboxGlyphsUnicode = CheckboxGlyphs{"☐", "☑", "⊟"}
+	CheckboxGlyphsASCII   = CheckboxGlyphs{"[ ]", "[x]", "[-]"}
+)
+
+// A box that can be checked and unchecked. The Value is controlled by the parent, which should update it when OnChange is called.
+type Checkbox struct {
+	Widget
+
+	Value    CheckboxValue
+	OnChange func(value CheckboxValue)
+	// Cycles through CheckboxIndeterminate when activated, instead of only toggling between checked and unchecked
+	Tristate bool
+	Label    string
+	// Defaults to CheckboxGlyphsUnicode
+	Glyphs    CheckboxGlyphs
+	Color     Color
+	Autofocus bool
+}
+
+var _ StateWidget = Checkbox{}
+
+func (w Checkbox) Build() (Widget, error) {
+	buttonState, focused := useActivatable(true, w.Autofocus, nil, func() {
+		if w.OnChange == nil {
+			return
+		}
+
+		switch {
+		case w.Value == CheckboxUnchecked:
+			w.OnChange(CheckboxChecked)
+		case w.Value == CheckboxChecked && w.Tristate:
+			w.OnChange(CheckboxIndeterminate)
+		default:
+			w.OnChange(CheckboxUnchecked)
+		}
+	})
+
+	glyphs := w.Glyphs
+	if glyphs == (CheckboxGlyphs{}) {
+		glyphs = CheckboxGlyphsUnicode
+	}
+
+	glyph := glyphs.Unchecked
+	switch w.Value {
+	case CheckboxChecked:
+		glyph = glyphs.Checked
+	case CheckboxIndeterminate:
+		glyph = glyphs.Indeterminate
+	}
+
+	return toggleText(glyph, w.Color, w.Label, buttonState, focused), nil
+}
+
+// The text displayed for each value of a Switch
+type SwitchGlyphs struct {
+	Off string
+	On  string
+}
+
+var (
+	SwitchGlyphsUnicode = SwitchGlyphs{"●──", "──●"}
+	SwitchGlyphsASCII   = SwitchGlyphs{"[o  ]", "[  o]"}
+)
+
+// A control that turns a setting on and off. The Value is controlled by the parent, which should update it when OnChange is called.
+type Switch struct {
+	Widget
+
+	Value    bool
+	OnChange func(value bool)
+	Label    string
+	// Defaults to SwitchGlyphsUnicode
+	Glyphs SwitchGlyphs
+	// Color of the switch when on, defaulting to green
+	OnColor   Color
+	Autofocus bool
+}
+
+var _ StateWidget = Switch{}
+
+func (w Switch) Build() (Widget, error) {
+	buttonState, focused := useActivatable(true, w.Autofocus, nil, func() {
+		if w.OnChange != nil {
+			w.OnChange(!w.Value)
+		}
+	})
+
+	glyphs := w.Glyphs
+	if glyphs == (SwitchGlyphs{}) {
+		glyphs = SwitchGlyphsUnicode
+	}
+
+	glyph := glyphs.Off
+	color := Color{}
+	if w.Value {
+		glyph = glyphs.On
+		color = w.OnColor
+		if color.A == 0 {
+			color = ColorRGB(0, 200, 0)
+		}
+	}
+
+	return toggleText(glyph, color, w.Label, buttonState, focused), nil
+}
+
+// The text displayed for options of a RadioGroup
+type RadioGlyphs struct {
+	Unselected string
+	Selected   string
+}
+
+var (
+	RadioGlyphsUnicode = RadioGlyphs{"○", "◉"}
+	RadioGlyphsASCII   = RadioGlyphs{"( )", "(*)"}
+)
+
+// One of the choices of a RadioGroup
+type RadioOption[T comparable] struct {
+	Value T
+	Label string
+}
+
+// A list of options, of which only one can be selected. Each option can be focused and selected on its own.
+// The Value is controlled by the parent, which should update it when OnChange is called.
+type RadioGroup[T comparable] struct {
+	Widget
+
+	Options  []RadioOption[T]
+	Value    T
+	OnChange func(value T)
+	// Direction the options are laid out in, where AxisVertical places each on its own line
+	Direction Axis
+	// Gap between options
+	Spacing int
+	// Defaults to RadioGlyphsUnicode
+	Glyphs RadioGlyphs
+	Color  Color
+	// Focuses the selected option when the group is first mounted
+	Autofocus bool
+}
+
+var _ StateWidget = RadioGroup[int]{}
+
+func (w RadioGroup[T]) Build() (Widget, error) {
+	glyphs := w.Glyphs
+	if glyphs == (RadioGlyphs{}) {
+		glyphs = RadioGlyphsUnicode
+	}
+
+	children := []Widget{}
+	for i, option := range w.Options {
+		if i > 0 && w.Spacing > 0 {
+			if w.Direction == AxisHorizontal {
+				children = append(children, SizedBox{Width: w.Spacing})
+			} else {
+				children = append(children, SizedBox{Height: w.Spacing})
+			}
+		}
+
+		onSelect := func() {}
+		if w.OnChange != nil {
+			onSelect = func() { w.OnChange(option.Value) }
+		}
+
+		children = append(children, radioOption{
+			Label:     option.Label,
+			Selected:  option.Value == w.Value,
+			OnSelect:  onSelect,
+			Glyphs:    glyphs,
+			Color:     w.Color,
+			Autofocus: w.Autofocus && option.Value == w.Value,
+		})
+	}
+
+	return Flex{
+		Direction:          w.Direction,
+		Children:           children,
+		MainAxisShrinkWrap: true,
+	}, nil
+}
+
+type radioOption struct {
+	Widget
+
+	Label     string
+	Selected  bool
+	OnSelect  func()
+	Glyphs    RadioGlyphs
+	Color     Color
+	Autofocus bool
+}
+
+var _ StateWidget = radioOption{}
+
+func (w radioOption) Build() (Widget, error) {
+	buttonState, focused := useActivatable(true, w.Autofocus, nil, w.OnSelect)
+
+	glyph := w.Glyphs.Unselected
+	if w.Selected {
+		glyph = w.Glyphs.Selected
+	}
+
+	return toggleText(glyph, w.Color, w.Label, buttonState, focused), nil
+}
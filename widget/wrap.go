@@ -0,0 +1,164 @@
+package goatw
+
+import (
+	. "github.com/jwr1/goat"
+)
+
+// Places its children one after another like a Row or Column, starting a new run whenever the next child does not fit along the main axis.
+// Runs are placed one after another along the cross axis.
+type Wrap struct {
+	Widget
+
+	Children []Widget
+	// Direction of the main axis, where AxisHorizontal places children in rows and AxisVertical places them in columns.
+	// Like Flex, the zero value is AxisVertical, so set AxisHorizontal for the usual rows of tags or buttons.
+	Direction Axis
+	// Gap between children within a run
+	Spacing int
+	// Gap between runs
+	RunSpacing int
+	// How children are placed along the main axis within each run
+	Alignment MainAxisAlignment
+	// How runs are placed along the cross axis, when the Wrap is larger than its runs
+	RunAlignment MainAxisAlignment
+	// How children are placed along the cross axis within each run
+	CrossAxisAlignment CrossAxisAlignment
+	MainAxisShrinkWrap bool
+}
+
+var _ RenderWidget = Wrap{}
+
+type wrapRun struct {
+	// Index of the first child in the run, and one after the last
+	start, end    int
+	mainAxisSize  int
+	crossAxisSize int
+}
+
+func (w Wrap) Layout(context LayoutContext) (Size, error) {
+	isHorizontal := w.Direction == AxisHorizontal
+
+	mainAxisSize := func(s Size) int {
+		if isHorizontal {
+			return s.Width.Int()
+		} else {
+			return s.Height.Int()
+		}
+	}
+	crossAxisSize := func(s Size) int {
+		if isHorizontal {
+			return s.Height.Int()
+		} else {
+			return s.Width.Int()
+		}
+	}
+	sizeFromAxes := func(mainAxisSize, crossAxisSize int) Size {
+		if isHorizontal {
+			return SizeInt(mainAxisSize, crossAxisSize)
+		} else {
+			return SizeInt(crossAxisSize, mainAxisSize)
+		}
+	}
+	positionChild := func(key, mainAxisPos, crossAxisPos int) error {
+		if isHorizontal {
+			return context.PositionChild(key, Pos{X: mainAxisPos, Y: crossAxisPos})
+		} else {
+			return context.PositionChild(key, Pos{X: crossAxisPos, Y: mainAxisPos})
+		}
+	}
+
+	maxMainAxisSize := mainAxisSize(context.Constraints.Max)
+	childrenSizes := make([]Size, len(w.Children))
+	runs := []wrapRun{}
+
+	for i, child := range w.Children {
+		childSize, err := context.LayoutChild(i, child, Constraints{
+			Max: context.Constraints.Max,
+		})
+		if err != nil {
+			return Size{}, err
+		}
+		childrenSizes[i] = childSize
+
+		// Like words in wrapped text, a child that is too large for any run is given a run of its own
+		if len(runs) == 0 || runs[len(runs)-1].mainAxisSize+w.Spacing+mainAxisSize(childSize) > maxMainAxisSize {
+			runs = append(runs, wrapRun{start: i, end: i + 1, mainAxisSize: mainAxisSize(childSize), crossAxisSize: crossAxisSize(childSize)})
+			continue
+		}
+
+		run := &runs[len(runs)-1]
+		run.end = i + 1
+		run.mainAxisSize += w.Spacing + mainAxisSize(childSize)
+		run.crossAxisSize = max(run.crossAxisSize, crossAxisSize(childSize))
+	}
+
+	contentMainAxisSize := 0
+	contentCrossAxisSize := max(len(runs)-1, 0) * w.RunSpacing
+	for _, run := range runs {
+		contentMainAxisSize = max(contentMainAxisSize, run.mainAxisSize)
+		contentCrossAxisSize += run.crossAxisSize
+	}
+
+	finalMainAxisSize := maxMainAxisSize
+	if w.MainAxisShrinkWrap || maxMainAxisSize == DimensionInfinite.Int() {
+		finalMainAxisSize = contentMainAxisSize
+	}
+	size := sizeFromAxes(finalMainAxisSize, contentCrossAxisSize).Clamp(context.Constraints)
+
+	runPos, runGap := alignWrapItems(w.RunAlignment, crossAxisSize(size)-contentCrossAxisSize, len(runs))
+	for _, run := range runs {
+		childPos, childGap := alignWrapItems(w.Alignment, mainAxisSize(size)-run.mainAxisSize, run.end-run.start)
+
+		for i := run.start; i < run.end; i++ {
+			crossAxisPos := runPos
+			switch w.CrossAxisAlignment {
+			case CrossAxisAlignmentCenter:
+				crossAxisPos += (run.crossAxisSize - crossAxisSize(childrenSizes[i])) / 2
+			case CrossAxisAlignmentEnd:
+				crossAxisPos += run.crossAxisSize - crossAxisSize(childrenSizes[i])
+			}
+
+			err := positionChild(i, childPos, crossAxisPos)
+			if err != nil {
+				return Size{}, err
+			}
+			childPos += mainAxisSize(childrenSizes[i]) + w.Spacing + childGap
+		}
+
+		runPos += run.crossAxisSize + w.RunSpacing + runGap
+	}
+
+	return size, nil
+}
+
+func (w Wrap) Paint(context PaintContext) error {
+	return nil
+}
+
+// Returns where the first of count items should be placed, and the extra gap to leave after each item, to align them within the free space
+func alignWrapItems(alignment MainAxisAlignment, freeSpace, count int) (int, int) {
+	freeSpace = max(freeSpace, 0)
+	if count == 0 {
+		return 0, 0
+	}
+
+	switch alignment {
+	case MainAxisAlignmentEnd:
+		return freeSpace, 0
+	case MainAxisAlignmentCenter:
+		return freeSpace / 2, 0
+	case MainAxisAlignmentSpaceBetween:
+		if count == 1 {
+			return 0, 0
+		}
+		return 0, freeSpace / (count - 1)
+	case MainAxisAlignmentSpaceAround:
+		gap := freeSpace / count
+		return gap / 2, gap
+	case MainAxisAlignmentSpaceEvenly:
+		gap := freeSpace / (count + 1)
+		return gap, gap
+	default:
+		return 0, 0
+	}
+}